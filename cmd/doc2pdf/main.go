@@ -2,11 +2,19 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/gogf/gf/v2/os/gcmd"
 	"github.com/gogf/gf/v2/os/gctx"
+	"github.com/gogf/gf/v2/os/gfile"
 	"github.com/hailaz/doc2pdf"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
 )
 
 var (
@@ -19,7 +27,7 @@ var (
 	pubArgs = []gcmd.Argument{
 		{
 			Name:  "index",
-			Brief: "confluence文档地址, https://goframe.org/display/gf",
+			Brief: "文档入口地址, https://goframe.org/display/gf",
 		},
 		{
 			Name:  "output",
@@ -30,14 +38,207 @@ var (
 			Brief: "下载模式，pdf或md，默认pdf",
 			Short: "m",
 		},
+		{
+			Name:  "timeout",
+			Brief: "整个任务的超时时间，如 2h，默认不限制",
+		},
+		{
+			Name:  "page-timeout",
+			Brief: "单个页面的超时时间，如 3m，默认3m",
+		},
+		{
+			Name:  "workers",
+			Brief: "同时渲染的页面数，默认1",
+			Short: "w",
+		},
+		{
+			Name:  "toc-file",
+			Brief: "目录文件，json或yaml，使用该文件代替菜单解析，可由toc命令导出后编辑",
+		},
+		{
+			Name:   "resume",
+			Brief:  "根据输出目录旁的清单恢复上次中断的下载",
+			Orphan: true,
+		},
+		{
+			Name:   "incremental",
+			Brief:  "增量导出，只重新渲染内容变化或新增的页面，变更报告保存在输出目录旁",
+			Orphan: true,
+		},
+		{
+			Name:   "toc-page",
+			Brief:  "在合并后的pdf开头生成带页码和链接的目录页",
+			Orphan: true,
+		},
+		{
+			Name:   "cover",
+			Brief:  "在合并后的pdf开头生成封面，设置任一cover-*参数时自动开启",
+			Orphan: true,
+		},
+		{
+			Name:  "cover-title",
+			Brief: "封面标题，默认为输出目录名",
+		},
+		{
+			Name:  "cover-subtitle",
+			Brief: "封面副标题",
+		},
+		{
+			Name:  "cover-logo",
+			Brief: "封面logo，图片地址或本地文件",
+		},
+		{
+			Name:  "cover-version",
+			Brief: "封面显示的文档版本",
+		},
+		{
+			Name:  "cover-template",
+			Brief: "封面html模板文件(Go html/template)，覆盖适配器的默认封面",
+		},
+		{
+			Name:   "page-number",
+			Brief:  "合并后在每页添加页码，默认格式为 n / 总页数",
+			Orphan: true,
+		},
+		{
+			Name:  "page-number-format",
+			Brief: "页码格式，%p为页码，%P为总页数，默认\"%p / %P\"",
+		},
+		{
+			Name:  "page-number-pos",
+			Brief: "页码位置，tl、tc、tr、l、c、r、bl、bc、br，默认bc",
+		},
+		{
+			Name:   "running-header",
+			Brief:  "合并后在每页添加页眉，内容为所在的一级书签标题",
+			Orphan: true,
+		},
+		{
+			Name:  "running-header-pos",
+			Brief: "页眉位置，默认tc",
+		},
+		{
+			Name:  "stamp-font",
+			Brief: "页码和页眉的字体，默认Helvetica，中文标题需使用 pdfcpu fonts install 安装的字体名",
+		},
+		{
+			Name:  "stamp-font-size",
+			Brief: "页码和页眉的字号，默认9",
+		},
+		{
+			Name:  "stamp-color",
+			Brief: "页码和页眉的颜色，默认#808080",
+		},
+		{
+			Name:  "stamp-pages",
+			Brief: "添加页码和页眉的页面，如 \"3-\"、\"odd\"、\"1-,!2\"，默认跳过封面和目录页",
+		},
 	}
 
-	confluence = &gcmd.Command{
-		Name:        "confluence",
-		Brief:       "confluence文档转换为pdf，doc2pdf confluence -h",
-		Description: "doc2pdf confluence --index=\"https://goframe.org/display/gf\" --output=\"./output/temp\"",
-		Arguments:   pubArgs,
-		Func:        confluenceFunc,
+	// browserArgs 浏览器参数
+	browserArgs = []gcmd.Argument{
+		{
+			Name:  "browser-url",
+			Brief: "连接已运行的浏览器，如 ws://127.0.0.1:9222/devtools/browser/xxx 或 127.0.0.1:9222，不再启动浏览器",
+		},
+		{
+			Name:  "browser-bin",
+			Brief: "浏览器路径，默认自动查找",
+		},
+		{
+			Name:   "headful",
+			Brief:  "显示浏览器窗口，用于调试",
+			Orphan: true,
+		},
+	}
+
+	// authArgs 访问需要登录的站点
+	authArgs = []gcmd.Argument{
+		{
+			Name:  "auth",
+			Brief: "登录配置文件(yaml/json)，包含cookieFile、headers和login脚本，密码和token使用${NAME}从环境变量读取",
+		},
+		{
+			Name:  "cookie-file",
+			Brief: "Netscape(cookies.txt)或json格式的cookie文件",
+		},
+		{
+			Name:  "header",
+			Brief: "附加的http头，如 \"Authorization: Bearer ${DOCS_TOKEN}\"",
+		},
+	}
+
+	list = &gcmd.Command{
+		Name:        "list",
+		Brief:       "列出支持的文档类型",
+		Description: "doc2pdf list",
+		Func:        listFunc,
+	}
+
+	toc = &gcmd.Command{
+		Name:        "toc",
+		Brief:       "导出文档目录为json或yaml，doc2pdf toc 文档类型 -h",
+		Description: "doc2pdf toc confluence --index=\"https://goframe.org/display/gf\" --format=yaml --file=toc.yaml",
+	}
+
+	// tocArgs toc命令的参数
+	tocArgs = []gcmd.Argument{
+		{
+			Name:  "index",
+			Brief: "文档入口地址, https://goframe.org/display/gf",
+		},
+		{
+			Name:  "output",
+			Brief: "输出目录，用于生成目录中的文件路径, ./output/temp",
+		},
+		{
+			Name:  "mode",
+			Brief: "下载模式，pdf或md，默认pdf",
+			Short: "m",
+		},
+		{
+			Name:  "timeout",
+			Brief: "整个任务的超时时间，如 2h，默认不限制",
+		},
+		{
+			Name:  "page-timeout",
+			Brief: "单个页面的超时时间，如 3m，默认3m",
+		},
+		{
+			Name:  "format",
+			Brief: "目录格式，json或yaml，默认json，保存到文件且未指定时根据文件扩展名判断",
+			Short: "f",
+		},
+		{
+			Name:  "file",
+			Brief: "保存目录的文件，默认输出到标准输出",
+		},
+	}
+
+	diff = &gcmd.Command{
+		Name:        "diff",
+		Brief:       "对比两次markdown模式导出的文档，生成变更报告，doc2pdf diff -h",
+		Description: "doc2pdf diff --old=\"./output/v1-md\" --new=\"./output/v2-md\" --format=html --file=changes.html",
+		Arguments: []gcmd.Argument{
+			{
+				Name:  "old",
+				Brief: "旧版本的markdown输出目录，需要保留同名的-html缓存目录, ./output/v1-md",
+			},
+			{
+				Name:  "new",
+				Brief: "新版本的markdown输出目录, ./output/v2-md",
+			},
+			{
+				Name:  "format",
+				Brief: "报告格式，md或html，默认md",
+				Short: "f",
+			},
+			{
+				Name:  "file",
+				Brief: "保存报告的文件，默认输出到标准输出",
+			},
+		},
+		Func: diffFunc,
 	}
 
 	goframeOld = &gcmd.Command{
@@ -56,17 +257,9 @@ var (
 		Name:        "gf",
 		Brief:       "GoFrame文档转换为pdf，doc2pdf gf -h",
 		Description: "doc2pdf gf",
-		Arguments:   pubArgs,
+		Arguments:   goframeArguments(),
 		Func:        goframeFunc,
 	}
-
-	docusaurus = &gcmd.Command{
-		Name:        "docusaurus",
-		Brief:       "docusaurus文档转换为pdf，doc2pdf docusaurus -h",
-		Description: "doc2pdf docusaurus --index=\"http://www.hailaz.cn/docs/learn/index\" --output=\"./output/hailaz-learn\"",
-		Arguments:   pubArgs,
-		Func:        docusaurusFunc,
-	}
 )
 
 // main description
@@ -79,26 +272,345 @@ func main() {
 
 	// doc2pdf.DownloadGoFrameAll()
 	// doc2pdf.DownloadGoFrameLatest()
-	err := Main.AddCommand(goframe, confluence, docusaurus)
-	if err != nil {
+	if err := initCommands(); err != nil {
 		panic(err)
 	}
-	Main.Run(gctx.New())
+	// Ctrl-C 时取消任务，关闭浏览器并清理临时文件
+	ctx, stop := signal.NotifyContext(gctx.New(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	Main.Run(ctx)
+}
+
+// initCommands 注册命令，每个适配器生成一个下载命令和一个toc子命令
+func initCommands() error {
+	if err := Main.AddCommand(goframe, list, toc, diff); err != nil {
+		return err
+	}
+	for _, name := range doc2pdf.AdapterNames() {
+		if err := Main.AddCommand(adapterCommand(name)); err != nil {
+			return err
+		}
+		if err := toc.AddCommand(tocCommand(name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// docOptions 解析公共参数
+func docOptions(parser *gcmd.Parser) ([]doc2pdf.DocOption, error) {
+	opts := make([]doc2pdf.DocOption, 0)
+	if v := parser.GetOpt("timeout"); v != nil {
+		d, err := time.ParseDuration(v.String())
+		if err != nil {
+			return nil, fmt.Errorf("timeout参数错误: %w", err)
+		}
+		opts = append(opts, doc2pdf.WithRunTimeout(d))
+	}
+	if v := parser.GetOpt("page-timeout"); v != nil {
+		d, err := time.ParseDuration(v.String())
+		if err != nil {
+			return nil, fmt.Errorf("page-timeout参数错误: %w", err)
+		}
+		opts = append(opts, doc2pdf.WithPageTimeout(d))
+	}
+	if v := parser.GetOpt("workers"); v != nil {
+		if v.Int() < 1 {
+			return nil, fmt.Errorf("workers参数错误: %s", v.String())
+		}
+		opts = append(opts, doc2pdf.WithWorkers(v.Int()))
+	}
+	if v := parser.GetOpt("toc-file"); v != nil {
+		opts = append(opts, doc2pdf.WithTOCFile(v.String()))
+	}
+	if parser.GetOpt("resume") != nil {
+		opts = append(opts, doc2pdf.WithResume(true))
+	}
+	if parser.GetOpt("incremental") != nil {
+		opts = append(opts, doc2pdf.WithIncremental(true))
+	}
+	if parser.GetOpt("toc-page") != nil {
+		opts = append(opts, doc2pdf.WithTOCPage(true))
+	}
+	if cover := coverOptions(parser); cover != nil {
+		opts = append(opts, doc2pdf.WithCover(cover))
+	}
+	stamp, err := stampOptions(parser)
+	if err != nil {
+		return nil, err
+	}
+	if stamp != nil {
+		opts = append(opts, doc2pdf.WithStamp(stamp))
+	}
+	auth, err := authConfig(parser)
+	if err != nil {
+		return nil, err
+	}
+	if auth != nil {
+		opts = append(opts, doc2pdf.WithAuth(auth))
+	}
+	opts = append(opts, doc2pdf.WithBrowserOptions(browserOptions(parser)))
+	return opts, nil
 }
 
-// confluenceFunc description
-func confluenceFunc(ctx context.Context, parser *gcmd.Parser) (err error) {
+// coverOptions 解析封面参数，未开启封面时返回nil
+func coverOptions(parser *gcmd.Parser) *doc2pdf.CoverOptions {
+	cover := &doc2pdf.CoverOptions{
+		Title:    parser.GetOpt("cover-title", "").String(),
+		Subtitle: parser.GetOpt("cover-subtitle", "").String(),
+		Logo:     parser.GetOpt("cover-logo", "").String(),
+		Version:  parser.GetOpt("cover-version", "").String(),
+		Template: parser.GetOpt("cover-template", "").String(),
+	}
+	if parser.GetOpt("cover") == nil && *cover == (doc2pdf.CoverOptions{}) {
+		return nil
+	}
+	return cover
+}
+
+// stampOptions 解析页码和页眉参数，都未开启时返回nil
+func stampOptions(parser *gcmd.Parser) (*doc2pdf.StampOptions, error) {
+	stamp := &doc2pdf.StampOptions{
+		PageNumber:       parser.GetOpt("page-number") != nil,
+		PageNumberFormat: parser.GetOpt("page-number-format", "").String(),
+		PageNumberPos:    parser.GetOpt("page-number-pos", "").String(),
+		Header:           parser.GetOpt("running-header") != nil,
+		HeaderPos:        parser.GetOpt("running-header-pos", "").String(),
+		Font:             parser.GetOpt("stamp-font", "").String(),
+		FontSize:         parser.GetOpt("stamp-font-size", 0).Int(),
+		Color:            parser.GetOpt("stamp-color", "").String(),
+	}
+	if !stamp.PageNumber && !stamp.Header {
+		return nil, nil
+	}
+	if v := parser.GetOpt("stamp-pages"); v != nil {
+		pages, err := api.ParsePageSelection(v.String())
+		if err != nil {
+			return nil, fmt.Errorf("stamp-pages参数错误: %w", err)
+		}
+		stamp.Pages = pages
+	}
+	// 提前检查字体和位置，避免渲染完成后才失败
+	if err := stamp.Validate(); err != nil {
+		return nil, err
+	}
+	return stamp, nil
+}
+
+// browserOptions 解析浏览器参数
+func browserOptions(parser *gcmd.Parser) doc2pdf.BrowserOptions {
+	return doc2pdf.BrowserOptions{
+		ControlURL: parser.GetOpt("browser-url", "").String(),
+		Bin:        parser.GetOpt("browser-bin", "").String(),
+		Headful:    parser.GetOpt("headful") != nil,
+	}
+}
+
+// authConfig 解析登录参数，cookie-file和header会覆盖配置文件中的同名设置
+func authConfig(parser *gcmd.Parser) (*doc2pdf.AuthConfig, error) {
+	var auth *doc2pdf.AuthConfig
+	if v := parser.GetOpt("auth"); v != nil {
+		cfg, err := doc2pdf.LoadAuthConfig(v.String())
+		if err != nil {
+			return nil, err
+		}
+		auth = cfg
+	}
+	if v := parser.GetOpt("cookie-file"); v != nil {
+		if auth == nil {
+			auth = &doc2pdf.AuthConfig{}
+		}
+		auth.CookieFile = v.String()
+	}
+	if v := parser.GetOpt("header"); v != nil {
+		name, value, ok := strings.Cut(v.String(), ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("header参数格式应为\"名称: 值\"")
+		}
+		if auth == nil {
+			auth = &doc2pdf.AuthConfig{}
+		}
+		if auth.Headers == nil {
+			auth.Headers = make(map[string]string)
+		}
+		auth.Headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return auth, nil
+}
+
+// adapterArguments 适配器的额外参数
+func adapterArguments(adapter doc2pdf.SiteAdapter) []gcmd.Argument {
+	args := make([]gcmd.Argument, 0)
+	for _, opt := range adapter.Options() {
+		args = append(args, gcmd.Argument{
+			Name:   opt.Name,
+			Short:  opt.Short,
+			Brief:  opt.Brief,
+			Orphan: opt.Orphan,
+		})
+	}
+	return args
+}
+
+// newAdapter 创建适配器并设置命令行中的额外参数
+func newAdapter(parser *gcmd.Parser, name string) (doc2pdf.SiteAdapter, error) {
+	adapter, err := doc2pdf.NewAdapter(name)
+	if err != nil {
+		return nil, err
+	}
+	for _, opt := range adapter.Options() {
+		value := parser.GetOpt(opt.Name)
+		if value == nil {
+			continue
+		}
+		optValue := value.String()
+		if opt.Orphan && optValue == "" {
+			optValue = "true"
+		}
+		if err := adapter.SetOption(opt.Name, optValue); err != nil {
+			return nil, err
+		}
+	}
+	return adapter, nil
+}
+
+// adapterCommand 根据适配器生成命令
+func adapterCommand(name string) *gcmd.Command {
+	adapter, _ := doc2pdf.NewAdapter(name)
+	args := append([]gcmd.Argument{}, pubArgs...)
+	args = append(args, authArgs...)
+	args = append(args, browserArgs...)
+	args = append(args, adapterArguments(adapter)...)
+	return &gcmd.Command{
+		Name:        name,
+		Brief:       fmt.Sprintf("%s，doc2pdf %s -h", adapter.Brief(), name),
+		Description: fmt.Sprintf("doc2pdf %s --index=\"https://example.com/docs\" --output=\"./output/temp\"", name),
+		Arguments:   args,
+		Func: func(ctx context.Context, parser *gcmd.Parser) (err error) {
+			return adapterFunc(ctx, parser, name)
+		},
+	}
+}
+
+// adapterFunc description
+func adapterFunc(ctx context.Context, parser *gcmd.Parser, name string) (err error) {
 	// go run main.go confluence --index="https://goframe.org/display/gf" --output="./output/temp"
 	index := parser.GetOpt("index")
 	output := parser.GetOpt("output")
 	inMode := parser.GetOpt("mode", doc2pdf.DocDownloadModePDF)
-	log.Printf("index: %v, output: %v, mode: %v", index, output, inMode)
+	log.Printf("adapter: %s, index: %v, output: %v, mode: %v", name, index, output, inMode)
 	if index == nil || output == nil {
 		log.Printf("index or output is nil")
 		return
 	}
+	adapter, err := newAdapter(parser, name)
+	if err != nil {
+		return err
+	}
+
+	opts, err := docOptions(parser)
+	if err != nil {
+		return err
+	}
 
-	doc2pdf.DownloadConfluence(index.String(), output.String(), inMode.String(), false)
+	summary, err := doc2pdf.DownloadWithAdapter(ctx, adapter, index.String(), output.String(), inMode.String(), opts...)
+	if summary != nil {
+		log.Println(summary)
+	}
+	return err
+}
+
+// tocCommand 根据适配器生成toc子命令，参数与下载命令中的适配器参数一致
+func tocCommand(name string) *gcmd.Command {
+	adapter, _ := doc2pdf.NewAdapter(name)
+	args := append([]gcmd.Argument{}, tocArgs...)
+	args = append(args, authArgs...)
+	args = append(args, browserArgs...)
+	args = append(args, adapterArguments(adapter)...)
+	return &gcmd.Command{
+		Name:        name,
+		Brief:       fmt.Sprintf("导出%s目录，doc2pdf toc %s -h", name, name),
+		Description: fmt.Sprintf("doc2pdf toc %s --index=\"https://example.com/docs\" --format=yaml --file=toc.yaml", name),
+		Arguments:   args,
+		Func: func(ctx context.Context, parser *gcmd.Parser) (err error) {
+			return tocFunc(ctx, parser, name)
+		},
+	}
+}
+
+// listFunc description
+func listFunc(ctx context.Context, parser *gcmd.Parser) (err error) {
+	for _, name := range doc2pdf.AdapterNames() {
+		adapter, err := doc2pdf.NewAdapter(name)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%-12s %s\n", name, adapter.Brief())
+	}
+	return
+}
+
+// tocFunc 导出目录
+func tocFunc(ctx context.Context, parser *gcmd.Parser, name string) (err error) {
+	// go run main.go toc confluence --index="https://goframe.org/display/gf" --format=yaml
+	index := parser.GetOpt("index")
+	output := parser.GetOpt("output", "./output/temp")
+	inMode := parser.GetOpt("mode", doc2pdf.DocDownloadModePDF)
+	if index == nil {
+		log.Printf("index is nil")
+		return
+	}
+	adapter, err := newAdapter(parser, name)
+	if err != nil {
+		return err
+	}
+	opts, err := docOptions(parser)
+	if err != nil {
+		return err
+	}
+	tree, err := doc2pdf.DiscoverWithAdapter(ctx, adapter, index.String(), output.String(), inMode.String(), opts...)
+	if err != nil {
+		return err
+	}
+	format := parser.GetOpt("format")
+	file := parser.GetOpt("file")
+	if file != nil && format == nil {
+		return doc2pdf.SaveDocTree(tree, file.String())
+	}
+	data, err := tree.Encode(parser.GetOpt("format", doc2pdf.TreeFormatJSON).String())
+	if err != nil {
+		return err
+	}
+	if file != nil {
+		return gfile.PutBytes(file.String(), data)
+	}
+	fmt.Println(string(data))
+	return
+}
+
+// diffFunc 对比两次导出
+func diffFunc(ctx context.Context, parser *gcmd.Parser) (err error) {
+	// go run main.go diff --old="./output/v1-md" --new="./output/v2-md"
+	oldDir := parser.GetOpt("old")
+	newDir := parser.GetOpt("new")
+	format := parser.GetOpt("format", doc2pdf.DiffFormatMarkdown)
+	if oldDir == nil || newDir == nil {
+		log.Printf("old or new is nil")
+		return
+	}
+	report, err := doc2pdf.DiffExports(oldDir.String(), newDir.String())
+	if err != nil {
+		return err
+	}
+	content, err := report.Render(format.String())
+	if err != nil {
+		return err
+	}
+	if file := parser.GetOpt("file"); file != nil {
+		log.Println(report)
+		return gfile.PutContents(file.String(), content)
+	}
+	fmt.Print(content)
 	return
 }
 
@@ -110,35 +622,36 @@ func goframeFuncOld(ctx context.Context, parser *gcmd.Parser) (err error) {
 	if version != nil {
 		switch version.String() {
 		case "all":
-			doc2pdf.DownloadGoFrameAll(inMode.String())
+			return doc2pdf.DownloadGoFrameAll(ctx, inMode.String())
 		default:
-			doc2pdf.DownloadGoFrameWithVersion(version.String(), inMode.String())
+			_, err = doc2pdf.DownloadGoFrameWithVersion(ctx, version.String(), inMode.String())
 		}
 	} else {
-		doc2pdf.DownloadGoFrameLatest(inMode.String())
+		_, err = doc2pdf.DownloadGoFrameLatest(ctx, inMode.String())
 	}
 	return
 }
 
-// goframeFunc description
-func goframeFunc(ctx context.Context, parser *gcmd.Parser) (err error) {
-	index := parser.GetOpt("index")
-	log.Printf("index: %v", index)
-	doc2pdf.DownloadGoFrame(index.String())
-	return
+// goframeArguments gf命令的参数，四个文档输出到固定的目录，不支持output和toc-file
+func goframeArguments() []gcmd.Argument {
+	args := make([]gcmd.Argument, 0, len(pubArgs)+len(browserArgs))
+	for _, arg := range pubArgs {
+		if arg.Name == "output" || arg.Name == "toc-file" {
+			continue
+		}
+		args = append(args, arg)
+	}
+	return append(args, browserArgs...)
 }
 
-// docusaurusFunc description
-func docusaurusFunc(ctx context.Context, parser *gcmd.Parser) (err error) {
-	// go run main.go docusaurus --index="http://www.hailaz.cn/docs/learn/index" --output="./output/hailaz-learn"
+// goframeFunc description
+func goframeFunc(ctx context.Context, parser *gcmd.Parser) (err error) {
 	index := parser.GetOpt("index")
-	output := parser.GetOpt("output")
-	log.Printf("index: %v, output: %v", index, output)
-	if index == nil || output == nil {
-		log.Printf("index or output is nil")
-		return
+	inMode := parser.GetOpt("mode", doc2pdf.DocDownloadModePDF)
+	log.Printf("index: %v, mode: %v", index, inMode)
+	opts, err := docOptions(parser)
+	if err != nil {
+		return err
 	}
-
-	doc2pdf.DownloadDocusaurus(index.String(), output.String())
-	return
+	return doc2pdf.DownloadGoFrame(ctx, index.String(), inMode.String(), opts...)
 }
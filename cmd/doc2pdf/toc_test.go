@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/gogf/gf/v2/encoding/gyaml"
+	"github.com/gogf/gf/v2/os/gcmd"
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/hailaz/doc2pdf"
+)
+
+func TestMain(m *testing.M) {
+	if err := initCommands(); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+// parseCommand 按命令的参数定义解析命令行
+func parseCommand(t *testing.T, cmd *gcmd.Command, args ...string) *gcmd.Parser {
+	t.Helper()
+	supported := make(map[string]bool)
+	for _, arg := range cmd.Arguments {
+		key := arg.Name
+		if arg.Short != "" {
+			key = fmt.Sprintf("%s,%s", arg.Name, arg.Short)
+		}
+		supported[key] = !arg.Orphan
+	}
+	parser, err := gcmd.ParseArgs(append([]string{"doc2pdf"}, args...), supported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parser
+}
+
+// TestTocAdapterOptions description
+//
+// createTime: 2026-10-18 09:15:17
+func TestTocAdapterOptions(t *testing.T) {
+	parser := parseCommand(t, tocCommand("confluence"), "--index=https://wiki.example.com/display/DOC", "--comments", "--timeout=1m")
+	adapter, err := newAdapter(parser, "confluence")
+	if err != nil {
+		t.Fatal(err)
+	}
+	confluence := adapter.(*doc2pdf.ConfluenceAdapter)
+	if !confluence.WithComments {
+		t.Errorf("unexpected options %+v", confluence)
+	}
+	if _, err := docOptions(parser); err != nil {
+		t.Fatal(err)
+	}
+
+	// toc子命令在启动浏览器前设置适配器参数
+	_, err = Main.RunWithSpecificArgs(context.Background(), []string{
+		"doc2pdf", "toc", "custom", "--index=http://127.0.0.1:1/", "--config=" + filepath.Join(t.TempDir(), "missing.yaml"),
+	})
+	if err == nil || !strings.Contains(err.Error(), "配置文件不存在") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+// TestTocCustomFormat description
+//
+// createTime: 2026-10-18 09:37:22
+func TestTocCustomFormat(t *testing.T) {
+	if _, exists := launcher.LookPath(); !exists {
+		t.Skip("未找到浏览器")
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, `<html><body><nav><ul>
+			<li><a href="/docs/start">Start</a><ul><li><a href="/docs/install">Install</a></li></ul></li>
+			<li><a href="/docs/config">Config</a></li>
+		</ul></nav><main>doc</main></body></html>`)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	config := filepath.Join(dir, "site.yaml")
+	if err := gfile.PutContents(config, "menu:\n  root: nav > ul\n"); err != nil {
+		t.Fatal(err)
+	}
+	// 指定format时不根据文件扩展名判断格式
+	file := filepath.Join(dir, "toc.json")
+	_, err := Main.RunWithSpecificArgs(context.Background(), []string{
+		"doc2pdf", "toc", "custom", "--index=" + server.URL + "/docs/", "--output=" + filepath.Join(dir, "out"),
+		"--config=" + config, "--format=yaml", "--file=" + file,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree := doc2pdf.NewDocTree("")
+	if err := gyaml.DecodeTo(gfile.GetBytes(file), tree); err != nil {
+		t.Fatalf("toc file should be yaml: %v", err)
+	}
+	if len(tree.Nodes) != 2 || tree.Nodes[0].Title != "Start" || len(tree.Nodes[0].Children) != 1 {
+		t.Errorf("unexpected tree %+v", tree.Nodes)
+	}
+}
+
+// TestGoFrameOptions gf命令与适配器命令使用同样的公共参数
+//
+// createTime: 2026-10-18 09:15:17
+func TestGoFrameOptions(t *testing.T) {
+	for _, arg := range goframe.Arguments {
+		if arg.Name == "output" || arg.Name == "toc-file" {
+			t.Errorf("gf should not accept --%s", arg.Name)
+		}
+	}
+	parser := parseCommand(t, goframe, "--workers=3", "--page-timeout=1m", "--page-number", "--headful")
+	opts, err := docOptions(parser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := &doc2pdf.DocDownload{}
+	for _, opt := range opts {
+		opt(doc)
+	}
+	if doc.Workers != 3 || doc.PageTimeout != time.Minute || doc.Stamp == nil || !doc.Stamp.PageNumber {
+		t.Errorf("workers = %d, page timeout = %s, stamp = %+v", doc.Workers, doc.PageTimeout, doc.Stamp)
+	}
+}
@@ -1,533 +1,1026 @@
-package doc2pdf
-
-import (
-	"fmt"
-	"io"
-	"log"
-	"net/url"
-	"os"
-	"path"
-	"path/filepath"
-	"strings"
-	"time"
-
-	"github.com/go-rod/rod"
-	"github.com/go-rod/rod/lib/launcher"
-	"github.com/go-rod/rod/lib/proto"
-	"github.com/go-rod/rod/lib/utils"
-	"github.com/gogf/gf/v2/os/gfile"
-	"github.com/pdfcpu/pdfcpu/pkg/api"
-	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
-)
-
-const (
-	// DocDownloadModePDF pdf模式
-	DocDownloadModePDF = "pdf"
-	// DocDownloadModeMD markdown模式
-	DocDownloadModeMD = "md"
-)
-
-// DocDownload description
-type DocDownload struct {
-	MainURL        string // 文档入口地址
-	outputDir      string // 输出目录
-	MergePDFNums   int    // 每次合并的pdf数量，多文档时能减轻内存压力
-	TempSuffix     string // 临时文件后缀
-	IsDownloadMain bool
-
-	pageFrom int
-	baseURL  string
-	browser  *rod.Browser
-	OpDelay  time.Duration
-
-	Mode string // 下载模式: pdf,md
-
-	// for pdf
-	fileList      []string
-	bookmark      []pdfcpu.Bookmark
-	SavePDFBefore func(page *rod.Page)
-	PageToPDF     func(page *rod.Page, filePath string) error
-	// for markdown
-	PageToMD func(doc *DocDownload, filePath string, pageUrl string) error
-
-	// menu
-	MenuRootSelector string
-	ParseMenu        func(doc *DocDownload, root *rod.Element, level int, dirPath string, bms *[]pdfcpu.Bookmark) // 解析菜单
-
-	// 单文件最大页面数
-	MaxPage int
-	// 切分后的文件列表
-	SplitFiles []string
-}
-
-// NewDocDownload description
-//
-// createTime: 2023-07-26 11:42:19
-//
-// author: hailaz
-func NewDocDownload(mainURL, outputDir string) *DocDownload {
-	log.SetFlags(log.Llongfile | log.Ldate | log.Ltime)
-	var browser *rod.Browser
-	var launcherSet = launcher.New().Leakless(false)
-	// 是否调试
-	var isDebug = false
-	if isDebug {
-		launcherSet.Headless(false)
-	}
-	if binPath, exists := launcher.LookPath(); exists {
-		log.Println("找到浏览器", binPath)
-		launcherSet.Bin(binPath)
-	} else {
-		// 如果没有找到浏览器，就使用默认的浏览器
-	}
-	u, err := launcherSet.Launch()
-	if err != nil {
-		panic(err)
-	}
-	log.Println("浏览器启动成功", u)
-
-	browser = rod.New().ControlURL(u).MustConnect()
-	if isDebug {
-		browser.SlowMotion(time.Second * 2)
-	}
-	// 从mainURL获取baseURL
-	parsedURL, err := url.Parse(mainURL)
-	if err != nil {
-		log.Println("url.Parse Error:", err)
-		return nil
-	}
-	baseURL := fmt.Sprintf("%s://%s", parsedURL.Scheme, parsedURL.Host)
-	return &DocDownload{
-		MainURL:        mainURL,
-		outputDir:      path.Join(outputDir),
-		MergePDFNums:   20,
-		TempSuffix:     ".temp.pdf",
-		IsDownloadMain: false,
-		fileList:       make([]string, 0),
-		bookmark:       make([]pdfcpu.Bookmark, 0),
-		pageFrom:       1,
-		browser:        browser.Trace(false),
-		baseURL:        baseURL,
-		OpDelay:        200 * time.Millisecond,
-		PageToPDF:      PageToPDF,
-		Mode:           DocDownloadModePDF,
-		MaxPage:        100,
-	}
-}
-
-// Start 开始任务
-//
-// createTime: 2023-07-28 15:03:11
-//
-// author: hailaz
-func (doc *DocDownload) Start() {
-	doc.Show()
-	log.Println("判断是否保存入口页")
-
-	if doc.Mode == DocDownloadModeMD {
-		gfile.Remove(doc.OutputDir())
-		if doc.IsDownloadMain {
-			doc.Index(&doc.bookmark)
-		}
-		if doc.ParseMenu != nil {
-			log.Println("菜单解析")
-			root := doc.GetMenuRoot(doc.MenuRootSelector)
-			doc.ParseMenu(doc, root, 0, doc.OutputDir(), nil)
-		}
-	} else if doc.Mode == DocDownloadModePDF {
-		if doc.IsDownloadMain {
-			doc.Index(&doc.bookmark)
-		}
-		if doc.ParseMenu != nil {
-			log.Println("菜单解析")
-			root := doc.GetMenuRoot(doc.MenuRootSelector)
-			doc.ParseMenu(doc, root, 0, doc.OutputDir(), &doc.bookmark)
-		}
-
-		log.Println("判断是否合并文件")
-		if len(doc.fileList) > 0 {
-			doc.MrPDF()
-		}
-
-		doc.AddBookmarks()
-
-		doc.SplitPDF()
-	}
-	// 关闭浏览器
-	doc.Close()
-}
-
-// GetBrowser 返回浏览器对象
-//
-// createTime: 2023-07-28 14:23:07
-//
-// author: hailaz
-func (doc *DocDownload) GetBrowser() *rod.Browser {
-	return doc.browser
-}
-
-// SetDebug description
-//
-// createTime: 2025-02-13 14:39:22
-func (doc *DocDownload) SetDebug() {
-	doc.browser.SlowMotion(time.Second).Trace(true)
-}
-
-// OutputDir description
-//
-// createTime: 2024-02-05 15:57:59
-func (doc *DocDownload) OutputDir() string {
-	if doc.Mode == DocDownloadModeMD {
-		return doc.outputDir + "-md"
-	}
-	return doc.outputDir
-}
-
-// OutputPDF description
-//
-// createTime: 2024-02-05 15:57:59
-func (doc *DocDownload) OutputPDF() string {
-	return doc.OutputDir() + ".pdf"
-}
-
-// StaticDir description
-//
-// createTime: 2024-02-05 15:57:59
-func (doc *DocDownload) StaticDir() string {
-	return doc.OutputDir() + "-static"
-}
-
-// HTMLDir description
-//
-// createTime: 2024-02-05 15:57:59
-func (doc *DocDownload) HTMLDir() string {
-	return doc.OutputDir() + "-html"
-}
-
-// Close 关闭
-//
-// createTime: 2023-07-28 14:23:07
-//
-// author: hailaz
-func (doc *DocDownload) Close() error {
-	doc.browser.Close()
-	return nil
-}
-
-// Show description
-//
-// createTime: 2023-07-26 14:15:15
-//
-// author: hailaz
-func (doc *DocDownload) Show() {
-	fmt.Println("MainURL:", doc.MainURL)
-	fmt.Println("OutputDir:", doc.OutputDir())
-	fmt.Println("baseURL:", doc.baseURL)
-}
-
-// MrPDF description
-//
-// createTime: 2023-07-26 14:18:53
-//
-// author: hailaz
-func (doc *DocDownload) MrPDF() {
-	fLen := len(doc.fileList)
-	preNum := doc.MergePDFNums
-	fileName := doc.TempSuffix
-	if preNum < 2 {
-		preNum = 2
-	}
-
-	if fLen > 0 {
-		index := 0
-		tempOldName := ""
-		tempName := fmt.Sprintf("%s.%d%s", doc.OutputDir(), index, fileName)
-		for {
-			if index+preNum >= fLen {
-				log.Printf("最后合并%d-%d(%d)", index, fLen, fLen)
-				if index == 0 {
-					api.MergeCreateFile(doc.fileList[index:fLen], doc.OutputDir()+fileName, false, nil)
-				} else {
-					api.MergeCreateFile(append([]string{tempOldName}, doc.fileList[index:fLen]...), doc.OutputDir()+fileName, false, nil)
-					os.Remove(tempOldName)
-				}
-				break
-			}
-			log.Printf("临时合并%d-%d(%d)", index, index+preNum, fLen)
-			if index == 0 {
-				api.MergeCreateFile(doc.fileList[index:index+preNum], tempName, false, nil)
-			} else {
-				api.MergeCreateFile(append([]string{tempOldName}, doc.fileList[index:index+preNum]...), tempName, false, nil)
-				os.Remove(tempOldName)
-			}
-
-			index += preNum
-			tempOldName = tempName
-			tempName = fmt.Sprintf("%s.%d%s", doc.OutputDir(), index, fileName)
-		}
-	}
-}
-
-// AddBookmarks 添加书签
-//
-// createTime: 2023-07-26 16:22:46
-//
-// author: hailaz
-func (doc *DocDownload) AddBookmarks() error {
-	log.Println("判断是否有书签数据")
-	if len(doc.bookmark) > 0 {
-		log.Println("添加书签", doc.OutputPDF())
-		// gutil.Dump(doc.bookmark)
-		return api.AddBookmarksFile(doc.OutputDir()+doc.TempSuffix, doc.OutputPDF(), doc.bookmark, true, nil)
-	}
-	return nil
-}
-
-// SplitPDF 根据最大页面数切分pdf
-//
-// createTime: 2023-07-26 16:22:46
-func (doc *DocDownload) SplitPDF() {
-	// 1. 读取PDF文件
-	pdfName := doc.OutputPDF()
-	log.Println("开始切分PDF", pdfName)
-	pageCount, err := api.PageCountFile(pdfName)
-	if err != nil {
-		log.Println("PageCountFile Error:", err)
-		return
-	}
-	// 2. 计算分割页数
-	maxPage := doc.MaxPage
-	if maxPage <= 0 {
-		log.Println("MaxPage必须大于0")
-		return
-	}
-	if pageCount <= maxPage {
-		log.Println("页面数小于MaxPage，无需切分")
-		return
-	}
-
-	fileList := make([]string, 0)
-	newFileList := make([]string, 0)
-	baseName := strings.TrimSuffix(pdfName, ".pdf")
-
-	var pageNrs []int
-	// 3. 执行分割
-	partIndex := 1
-	for i := 1; i <= pageCount; {
-		startPage := i
-		endPage := i + maxPage
-		if endPage > pageCount {
-			endPage = pageCount + 1
-		} else {
-			pageNrs = append(pageNrs, endPage)
-		}
-		// SplitByPageNrFile 会生成的文件名
-		originalFileName := fmt.Sprintf("%s_%d-%d.pdf", baseName, startPage, endPage-1)
-		fileList = append(fileList, originalFileName)
-
-		// 我们想要重命名为这个名称
-		newFileName := fmt.Sprintf("%s_part%d.pdf", baseName, partIndex)
-		newFileList = append(newFileList, newFileName)
-
-		i = endPage
-		partIndex++
-	}
-
-	log.Println("分割页数:", pageNrs)
-	err = api.SplitByPageNrFile(pdfName, filepath.Dir(doc.OutputDir()), pageNrs, nil)
-	if err != nil {
-		log.Println("SplitByPageNrFile Error:", err)
-		return
-	}
-
-	// 4. 重命名分割后的文件
-	for i, oldFile := range fileList {
-		if i < len(newFileList) {
-			err := os.Rename(oldFile, newFileList[i])
-			if err != nil {
-				log.Printf("重命名文件失败 %s -> %s: %v", oldFile, newFileList[i], err)
-			} else {
-				log.Printf("重命名文件成功 %s -> %s", oldFile, newFileList[i])
-			}
-		}
-	}
-
-	// 5. 保存分割后的文件列表
-	doc.SplitFiles = newFileList
-	log.Println("切分完成，文件列表:", doc.SplitFiles)
-}
-
-// GetMenuRoot description
-//
-// createTime: 2023-07-26 16:31:12
-//
-// author: hailaz
-func (doc *DocDownload) GetMenuRoot(selector string) *rod.Element {
-	page := doc.browser.MustPage(doc.MainURL).MustWaitStable()
-	page.SetWindow(&proto.BrowserBounds{WindowState: proto.BrowserWindowStateMaximized})
-	page.SetViewport(&proto.EmulationSetDeviceMetricsOverride{
-		Width:  1920,
-		Height: 100000,
-	})
-	return page.MustElement(selector)
-}
-
-// Index description
-//
-// createTime: 2023-07-28 14:42:42
-//
-// author: hailaz
-func (doc *DocDownload) Index(bms *[]pdfcpu.Bookmark) {
-	dirPath := doc.OutputDir()
-	text := "首页"
-
-	if doc.Mode == DocDownloadModePDF {
-		*bms = append(*bms, pdfcpu.Bookmark{
-			Title:    text,
-			PageFrom: doc.pageFrom,
-		})
-
-		fileName := fmt.Sprintf("%s.pdf", text)
-		filePath := path.Join(dirPath, fileName)
-		doc.fileList = append(doc.fileList, filePath)
-
-		err := doc.SavePDF(filePath, doc.MainURL)
-		if err != nil {
-			log.Println("SavePDF Error:", err)
-		}
-		page, err := api.PageCountFile(filePath)
-		if err != nil {
-			log.Println("SavePDF Error:", err)
-		}
-		doc.pageFrom = doc.pageFrom + page
-	} else {
-		fileNameMD := fmt.Sprintf("%s.md", text)
-		filePath := path.Join(dirPath, fileNameMD)
-		filePath = strings.ReplaceAll(filePath, "(🔥重点🔥)", "")
-		filePath = strings.ReplaceAll(filePath, "🔥", "")
-		filePath = strings.ReplaceAll(filePath, "(", "-")
-		filePath = strings.ReplaceAll(filePath, ")", "")
-		doc.SaveMD(filePath, doc.MainURL)
-	}
-}
-
-// SavePDF description
-//
-// createTime: 2023-07-11 16:51:31
-//
-// author: hailaz
-func (doc *DocDownload) SavePDF(filePath string, pageUrl string) error {
-	// log.Println("SavePDF", filePath)
-	if doc.PageToPDF == nil {
-		return nil
-	}
-	dir := path.Dir(filePath)
-	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		log.Println("创建目录", dir)
-		os.MkdirAll(dir, os.ModePerm)
-	}
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		page := doc.browser.MustPage(pageUrl).MustWaitStable()
-		defer page.Close()
-		if doc.SavePDFBefore != nil {
-			doc.SavePDFBefore(page)
-		}
-		if err := doc.PageToPDF(page, filePath); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-// SaveMD description
-//
-// createTime: 2023-07-11 16:51:31
-//
-// author: hailaz
-func (doc *DocDownload) SaveMD(filePath string, pageUrl string) error {
-	if doc.PageToMD == nil {
-		return nil
-	}
-	dir := path.Dir(filePath)
-	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		fmt.Println("创建目录", dir)
-		os.MkdirAll(dir, os.ModePerm)
-	}
-
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		dir = path.Dir(filePath)
-		if _, err := os.Stat(dir); os.IsNotExist(err) {
-			fmt.Println("创建目录", dir)
-			os.MkdirAll(dir, os.ModePerm)
-		}
-
-		if err := doc.PageToMD(doc, filePath, pageUrl); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
-// PageToPDF description
-//
-// createTime: 2023-07-28 16:45:39
-//
-// author: hailaz
-func PageToPDF(page *rod.Page, filePath string) error {
-	page.MustPDF(filePath)
-	return nil
-}
-
-// PageToPDFWithCfg description
-//
-// createTime: 2023-08-03 11:02:25
-//
-// author: hailaz
-func PageToPDFWithCfg(page *rod.Page, filePath string, req *proto.PagePrintToPDF) error {
-	r, err := page.PDF(req)
-	if err != nil {
-		log.Printf("PDF[err]: %s", err)
-		return err
-	}
-	bin, err := io.ReadAll(r)
-	if err != nil {
-		log.Printf("ReadAll[err]: %s", err)
-		return err
-	}
-	return utils.OutputFile(filePath, bin)
-}
-
-// Move 移动文件
-//
-// createTime: 2023-07-27 15:07:55
-//
-// author: hailaz
-func (doc *DocDownload) Move(targetDir string) error {
-	if _, err := os.Stat(targetDir); os.IsNotExist(err) {
-		fmt.Println("创建目录", targetDir)
-		os.MkdirAll(targetDir, os.ModePerm)
-	}
-
-	if len(doc.SplitFiles) > 0 {
-		for _, file := range doc.SplitFiles {
-			src := file
-			dst := path.Join(targetDir, path.Base(src))
-			if _, err := os.Stat(src); os.IsNotExist(err) {
-				continue
-			}
-			// 复制文件
-			os.Rename(src, dst)
-
-		}
-	}
-
-	src := doc.OutputPDF()
-	dst := path.Join(targetDir, path.Base(src))
-
-	if _, err := os.Stat(src); os.IsNotExist(err) {
-		return err
-	}
-	// 复制文件
-	return os.Rename(src, dst)
-
-}
+package doc2pdf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/devices"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/go-rod/rod/lib/utils"
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+)
+
+const (
+	// DocDownloadModePDF pdf模式
+	DocDownloadModePDF = "pdf"
+	// DocDownloadModeMD markdown模式
+	DocDownloadModeMD = "md"
+)
+
+// DocDownload description
+type DocDownload struct {
+	MainURL        string // 文档入口地址
+	outputDir      string // 输出目录
+	MergePDFNums   int    // 每次合并的pdf数量，多文档时能减轻内存压力
+	TempSuffix     string // 临时文件后缀
+	IsDownloadMain bool
+
+	baseURL string
+	browser *rod.Browser
+	OpDelay time.Duration
+	// 浏览器请求的Accept-Language，为空时使用浏览器默认值
+	AcceptLanguage string
+
+	browserOptions BrowserOptions // 任务自己启动或连接的浏览器参数
+	sharedBrowser  *Browser       // 浏览器，未共享时由任务创建
+	ownBrowser     bool           // 任务结束时是否关闭浏览器
+
+	ctx         context.Context
+	PageTimeout time.Duration // 单个页面的超时时间，0为不限制
+	RunTimeout  time.Duration // 整个任务的超时时间，0为不限制
+
+	Mode string // 下载模式: pdf,md
+
+	// 站点适配器
+	Adapter SiteAdapter
+
+	// 菜单解析得到的文档树
+	tree *DocTree
+	// 目录文件，设置后不再解析菜单，直接按目录文件渲染
+	TOCFile string
+	// 根据清单恢复上次中断的下载
+	Resume   bool
+	manifest *Manifest
+	// 增量导出，只重新渲染变化的页面
+	Incremental  bool
+	prevManifest *Manifest
+	changes      *ChangeReport
+	// 同时渲染的页面数
+	Workers  int
+	pagePool rod.Pool[rod.Page]
+
+	// for pdf
+	fileList []string
+	bookmark []pdfcpu.Bookmark
+
+	// 单文件最大页面数
+	MaxPage int
+	// 在合并后的pdf开头生成目录页
+	TOCPage bool
+	// 在合并后的pdf开头生成封面，为nil时不生成
+	Cover *CoverOptions
+	// 合并后在每页添加页码和页眉，为nil时不添加
+	Stamp *StampOptions
+	// 切分后的文件列表
+	SplitFiles []string
+
+	// 下载结果汇总
+	summary *RunSummary
+
+	// 登录配置
+	Auth       *AuthConfig
+	headers    []string     // 展开环境变量后的http头，成对保存名称和值
+	httpClient *http.Client // 登录后直接请求使用的客户端
+}
+
+// NewDocDownload 创建下载任务并启动或连接浏览器，opts在连接浏览器前应用
+//
+// createTime: 2023-07-26 11:42:19
+//
+// author: hailaz
+func NewDocDownload(mainURL, outputDir string, opts ...DocOption) (*DocDownload, error) {
+	doc, err := newDocDownload(mainURL, outputDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, opt := range opts {
+		opt(doc)
+	}
+	if err := doc.connect(); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// newDocDownload 创建使用默认参数的下载任务，不连接浏览器
+//
+// createTime: 2026-10-18 10:00:28
+func newDocDownload(mainURL, outputDir string) (*DocDownload, error) {
+	log.SetFlags(log.Llongfile | log.Ldate | log.Ltime)
+	// 从mainURL获取baseURL
+	parsedURL, err := url.Parse(mainURL)
+	if err != nil {
+		return nil, NewPageError(StageInit, mainURL, "", err)
+	}
+	baseURL := fmt.Sprintf("%s://%s", parsedURL.Scheme, parsedURL.Host)
+
+	return &DocDownload{
+		MainURL:        mainURL,
+		outputDir:      path.Join(outputDir),
+		MergePDFNums:   20,
+		TempSuffix:     ".temp.pdf",
+		IsDownloadMain: false,
+		fileList:       make([]string, 0),
+		bookmark:       make([]pdfcpu.Bookmark, 0),
+		baseURL:        baseURL,
+		OpDelay:        200 * time.Millisecond,
+		PageTimeout:    3 * time.Minute,
+		ctx:            context.Background(),
+		Adapter:        &BaseAdapter{},
+		Mode:           DocDownloadModePDF,
+		MaxPage:        100,
+		Workers:        1,
+		summary:        &RunSummary{},
+	}, nil
+}
+
+// connect 启动或连接浏览器并创建任务自己的浏览器上下文
+//
+// createTime: 2026-10-18 10:00:28
+func (doc *DocDownload) connect() error {
+	if doc.sharedBrowser == nil {
+		doc.sharedBrowser = NewBrowser(doc.browserOptions)
+		doc.ownBrowser = true
+	}
+	browser, err := doc.sharedBrowser.newContext()
+	if err != nil {
+		if doc.ownBrowser {
+			doc.sharedBrowser.Close()
+		}
+		return NewPageError(StageBrowser, doc.MainURL, "", err)
+	}
+	if doc.AcceptLanguage != "" {
+		browser = browser.DefaultDevice(devices.Device{AcceptLanguage: doc.AcceptLanguage})
+	}
+	doc.browser = browser
+	return nil
+}
+
+// DocOption 下载参数
+type DocOption func(doc *DocDownload)
+
+// WithPageTimeout 设置单个页面的超时时间
+//
+// createTime: 2026-10-18 09:08:35
+func WithPageTimeout(d time.Duration) DocOption {
+	return func(doc *DocDownload) {
+		doc.PageTimeout = d
+	}
+}
+
+// WithRunTimeout 设置整个任务的超时时间
+//
+// createTime: 2026-10-18 09:08:35
+func WithRunTimeout(d time.Duration) DocOption {
+	return func(doc *DocDownload) {
+		doc.RunTimeout = d
+	}
+}
+
+// WithTOCFile 使用目录文件代替菜单解析
+//
+// createTime: 2026-10-18 09:15:17
+func WithTOCFile(file string) DocOption {
+	return func(doc *DocDownload) {
+		doc.TOCFile = file
+	}
+}
+
+// WithResume 根据清单恢复上次中断的下载，只重新渲染失败或不完整的页面
+//
+// createTime: 2026-10-18 09:16:33
+func WithResume(resume bool) DocOption {
+	return func(doc *DocDownload) {
+		doc.Resume = resume
+	}
+}
+
+// Start 开始任务，单个页面失败不会中断任务，失败页面记录在 Summary 中。
+// ctx 取消或超时后会关闭浏览器并清理临时文件
+//
+// createTime: 2023-07-28 15:03:11
+//
+// author: hailaz
+func (doc *DocDownload) Start(ctx context.Context) error {
+	if doc.RunTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, doc.RunTimeout)
+		defer cancel()
+	}
+	doc.ctx = ctx
+	defer func() {
+		if ctx.Err() != nil {
+			doc.CleanTemp()
+		}
+	}()
+	// 关闭浏览器
+	defer doc.Close()
+	doc.Show()
+	log.Println("判断是否保存入口页")
+
+	var prev *Manifest
+	if doc.Resume || doc.Incremental {
+		m, err := LoadManifest(doc.ManifestFile())
+		if err != nil {
+			log.Println("无法恢复下载，重新开始:", err)
+		} else if m.Mode != doc.Mode {
+			log.Printf("清单模式%s与当前模式%s不一致，重新开始", m.Mode, doc.Mode)
+		} else {
+			prev = m
+		}
+	}
+	if doc.Mode == DocDownloadModeMD && prev == nil {
+		gfile.Remove(doc.OutputDir())
+	}
+	if err := doc.authenticate(); err != nil {
+		return NewPageError(StageAuth, doc.MainURL, "", err)
+	}
+	var (
+		tree *DocTree
+		err  error
+	)
+	if doc.TOCFile != "" {
+		log.Println("从目录文件读取文档树", doc.TOCFile)
+		if tree, err = LoadDocTree(doc.TOCFile); err != nil {
+			return NewPageError(StageMenu, doc.MainURL, doc.TOCFile, err)
+		}
+		tree.FillPaths(doc.OutputDir(), doc.FileExt())
+	} else if prev != nil && !doc.Incremental {
+		log.Println("从清单恢复文档树", doc.ManifestFile())
+		tree = prev.Tree
+	} else if tree, err = doc.Discover(ctx); err != nil {
+		return err
+	}
+	doc.tree = tree
+
+	doc.manifest = NewManifest(doc.ManifestFile(), doc.Mode, tree)
+	if doc.Incremental {
+		doc.prepareIncremental(prev, tree)
+		defer doc.finishIncremental()
+	} else if prev != nil {
+		for file, entry := range prev.Pages {
+			if _, ok := doc.manifest.Pages[file]; ok {
+				doc.manifest.Pages[file] = entry
+			}
+		}
+	}
+	if err := doc.manifest.Save(); err != nil {
+		log.Println("[err]保存清单失败:", err)
+	}
+
+	return doc.Exporter().Export(doc, tree)
+}
+
+// Discover 解析菜单生成文档树，不渲染页面
+//
+// createTime: 2026-10-18 09:15:17
+func (doc *DocDownload) Discover(ctx context.Context) (*DocTree, error) {
+	doc.ctx = ctx
+	tree := NewDocTree(doc.MainURL)
+	if doc.IsDownloadMain {
+		tree.Nodes = append(tree.Nodes, doc.Index())
+	}
+	var root *rod.Element
+	if selector := doc.Adapter.MenuRootSelector(); selector != "" {
+		log.Println("菜单解析")
+		var err error
+		root, err = doc.GetMenuRoot(selector)
+		if err != nil {
+			return nil, NewPageError(StageMenu, doc.MainURL, "", err)
+		}
+	}
+	// 不依赖页面菜单的适配器(如sitemap)root为nil，自行获取文档列表
+	nodes, err := doc.Adapter.ParseMenu(doc, root, 0, doc.OutputDir())
+	if err != nil {
+		return nil, NewPageError(StageMenu, doc.MainURL, "", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	tree.Nodes = append(tree.Nodes, nodes...)
+	if len(tree.Pages()) == 0 {
+		return nil, NewPageError(StageMenu, doc.MainURL, "", errors.New("菜单中没有找到页面"))
+	}
+	// 适配器未设置输出路径的节点按菜单顺序生成路径
+	tree.FillPaths(doc.OutputDir(), doc.FileExt())
+	return tree, nil
+}
+
+// Tree 返回菜单解析得到的文档树
+//
+// createTime: 2026-10-18 09:12:02
+func (doc *DocDownload) Tree() *DocTree {
+	return doc.tree
+}
+
+// Context 返回当前任务的context
+//
+// createTime: 2026-10-18 09:08:35
+func (doc *DocDownload) Context() context.Context {
+	return doc.ctx
+}
+
+// CleanTemp 清理合并过程中产生的临时文件
+//
+// createTime: 2026-10-18 09:08:35
+func (doc *DocDownload) CleanTemp() {
+	files, err := filepath.Glob(doc.OutputDir() + "*" + doc.TempSuffix)
+	if err != nil {
+		log.Println("CleanTemp Error:", err)
+		return
+	}
+	for _, file := range files {
+		log.Println("清理临时文件", file)
+		os.Remove(file)
+	}
+}
+
+// Summary 返回下载结果汇总
+//
+// createTime: 2026-10-18 09:07:09
+func (doc *DocDownload) Summary() *RunSummary {
+	return doc.summary
+}
+
+// AddResult 记录单个页面的处理结果，err为nil时记为成功
+//
+// createTime: 2026-10-18 09:07:09
+func (doc *DocDownload) AddResult(pageURL string, filePath string, err error) {
+	if err == nil {
+		doc.summary.addSuccess()
+		return
+	}
+	pageErr, ok := err.(*PageError)
+	if !ok {
+		pageErr = NewPageError(StagePage, pageURL, filePath, err)
+	}
+	log.Println("[err]", pageErr)
+	doc.summary.addFailure(pageErr)
+}
+
+// GetBrowser 返回浏览器对象
+//
+// createTime: 2023-07-28 14:23:07
+//
+// author: hailaz
+func (doc *DocDownload) GetBrowser() *rod.Browser {
+	return doc.browser
+}
+
+// SetDebug description
+//
+// createTime: 2025-02-13 14:39:22
+func (doc *DocDownload) SetDebug() {
+	doc.browser.SlowMotion(time.Second).Trace(true)
+}
+
+// OutputDir description
+//
+// createTime: 2024-02-05 15:57:59
+func (doc *DocDownload) OutputDir() string {
+	if doc.Mode == DocDownloadModeMD {
+		return doc.outputDir + "-md"
+	}
+	return doc.outputDir
+}
+
+// ManifestFile 下载清单文件，位于输出目录旁
+//
+// createTime: 2026-10-18 09:16:33
+func (doc *DocDownload) ManifestFile() string {
+	return doc.OutputDir() + ".manifest.json"
+}
+
+// FileExt 当前模式下输出文件的扩展名
+//
+// createTime: 2026-10-18 09:10:44
+func (doc *DocDownload) FileExt() string {
+	if doc.Mode == DocDownloadModeMD {
+		return ".md"
+	}
+	return ".pdf"
+}
+
+// OutputPDF description
+//
+// createTime: 2024-02-05 15:57:59
+func (doc *DocDownload) OutputPDF() string {
+	return doc.OutputDir() + ".pdf"
+}
+
+// StaticDir description
+//
+// createTime: 2024-02-05 15:57:59
+func (doc *DocDownload) StaticDir() string {
+	return doc.OutputDir() + "-static"
+}
+
+// HTMLDir description
+//
+// createTime: 2024-02-05 15:57:59
+func (doc *DocDownload) HTMLDir() string {
+	return doc.OutputDir() + "-html"
+}
+
+// Close 关闭
+//
+// createTime: 2023-07-28 14:23:07
+//
+// author: hailaz
+func (doc *DocDownload) Close() error {
+	if doc.browser == nil {
+		return nil
+	}
+	// 关闭任务的浏览器上下文，共享的浏览器由调用方关闭
+	err := doc.browser.Close()
+	doc.browser = nil
+	if doc.ownBrowser {
+		err = errors.Join(err, doc.sharedBrowser.Close())
+	}
+	return err
+}
+
+// Show description
+//
+// createTime: 2023-07-26 14:15:15
+//
+// author: hailaz
+func (doc *DocDownload) Show() {
+	fmt.Println("MainURL:", doc.MainURL)
+	fmt.Println("OutputDir:", doc.OutputDir())
+	fmt.Println("baseURL:", doc.baseURL)
+}
+
+// MrPDF description
+//
+// createTime: 2023-07-26 14:18:53
+//
+// author: hailaz
+func (doc *DocDownload) MrPDF() error {
+	fLen := len(doc.fileList)
+	preNum := doc.MergePDFNums
+	fileName := doc.TempSuffix
+	if preNum < 2 {
+		preNum = 2
+	}
+
+	if fLen > 0 {
+		index := 0
+		tempOldName := ""
+		tempName := fmt.Sprintf("%s.%d%s", doc.OutputDir(), index, fileName)
+		for {
+			if err := doc.ctx.Err(); err != nil {
+				return err
+			}
+			if index+preNum >= fLen {
+				log.Printf("最后合并%d-%d(%d)", index, fLen, fLen)
+				outFile := doc.OutputDir() + fileName
+				if index == 0 {
+					if err := api.MergeCreateFile(doc.fileList[index:fLen], outFile, false, nil); err != nil {
+						return NewPageError(StageMerge, "", outFile, err)
+					}
+				} else {
+					if err := api.MergeCreateFile(append([]string{tempOldName}, doc.fileList[index:fLen]...), outFile, false, nil); err != nil {
+						return NewPageError(StageMerge, "", outFile, err)
+					}
+					os.Remove(tempOldName)
+				}
+				break
+			}
+			log.Printf("临时合并%d-%d(%d)", index, index+preNum, fLen)
+			if index == 0 {
+				if err := api.MergeCreateFile(doc.fileList[index:index+preNum], tempName, false, nil); err != nil {
+					return NewPageError(StageMerge, "", tempName, err)
+				}
+			} else {
+				if err := api.MergeCreateFile(append([]string{tempOldName}, doc.fileList[index:index+preNum]...), tempName, false, nil); err != nil {
+					return NewPageError(StageMerge, "", tempName, err)
+				}
+				os.Remove(tempOldName)
+			}
+
+			index += preNum
+			tempOldName = tempName
+			tempName = fmt.Sprintf("%s.%d%s", doc.OutputDir(), index, fileName)
+		}
+	}
+	return nil
+}
+
+// AddBookmarks 添加书签
+//
+// createTime: 2023-07-26 16:22:46
+//
+// author: hailaz
+func (doc *DocDownload) AddBookmarks() error {
+	log.Println("判断是否有书签数据")
+	if len(doc.bookmark) > 0 {
+		log.Println("添加书签", doc.OutputPDF())
+		// gutil.Dump(doc.bookmark)
+		return api.AddBookmarksFile(doc.OutputDir()+doc.TempSuffix, doc.OutputPDF(), doc.bookmark, true, nil)
+	}
+	// 没有书签时直接使用合并后的文件
+	return os.Rename(doc.OutputDir()+doc.TempSuffix, doc.OutputPDF())
+}
+
+// SplitPDF 根据最大页面数切分pdf
+//
+// createTime: 2023-07-26 16:22:46
+func (doc *DocDownload) SplitPDF() error {
+	// 1. 读取PDF文件
+	pdfName := doc.OutputPDF()
+	log.Println("开始切分PDF", pdfName)
+	pageCount, err := api.PageCountFile(pdfName)
+	if err != nil {
+		return NewPageError(StageSplit, "", pdfName, err)
+	}
+	// 2. 计算分割页数
+	maxPage := doc.MaxPage
+	if maxPage <= 0 {
+		log.Println("MaxPage小于等于0，不切分")
+		return nil
+	}
+	if pageCount <= maxPage {
+		log.Println("页面数小于MaxPage，无需切分")
+		return nil
+	}
+
+	fileList := make([]string, 0)
+	newFileList := make([]string, 0)
+	baseName := strings.TrimSuffix(pdfName, ".pdf")
+
+	var pageNrs []int
+	// 3. 执行分割
+	partIndex := 1
+	for i := 1; i <= pageCount; {
+		startPage := i
+		endPage := i + maxPage
+		if endPage > pageCount {
+			endPage = pageCount + 1
+		} else {
+			pageNrs = append(pageNrs, endPage)
+		}
+		// SplitByPageNrFile 会生成的文件名
+		originalFileName := fmt.Sprintf("%s_%d-%d.pdf", baseName, startPage, endPage-1)
+		fileList = append(fileList, originalFileName)
+
+		// 我们想要重命名为这个名称
+		newFileName := fmt.Sprintf("%s_part%d.pdf", baseName, partIndex)
+		newFileList = append(newFileList, newFileName)
+
+		i = endPage
+		partIndex++
+	}
+
+	log.Println("分割页数:", pageNrs)
+	err = api.SplitByPageNrFile(pdfName, filepath.Dir(doc.OutputDir()), pageNrs, nil)
+	if err != nil {
+		return NewPageError(StageSplit, "", pdfName, err)
+	}
+
+	// 4. 重命名分割后的文件
+	for i, oldFile := range fileList {
+		if i < len(newFileList) {
+			err := os.Rename(oldFile, newFileList[i])
+			if err != nil {
+				return NewPageError(StageSplit, "", oldFile, err)
+			}
+			log.Printf("重命名文件成功 %s -> %s", oldFile, newFileList[i])
+		}
+	}
+
+	// 5. 保存分割后的文件列表
+	doc.SplitFiles = newFileList
+	log.Println("切分完成，文件列表:", doc.SplitFiles)
+	return nil
+}
+
+// SameSiteURL 去掉锚点的地址，站外地址返回空。#/和#!/开头的是hash路由，会保留
+//
+// createTime: 2026-10-18 09:23:12
+func (doc *DocDownload) SameSiteURL(href string) string {
+	u, err := url.Parse(href)
+	if err != nil || u.Scheme+"://"+u.Host != doc.baseURL {
+		return ""
+	}
+	if !IsHashRoute(u.Fragment) {
+		u.Fragment = ""
+		u.RawFragment = ""
+	}
+	return u.String()
+}
+
+// IsHashRoute 判断锚点是否为单页应用的hash路由
+//
+// createTime: 2026-10-18 09:33:26
+func IsHashRoute(fragment string) bool {
+	return strings.HasPrefix(fragment, "/") || strings.HasPrefix(fragment, "!/")
+}
+
+// GetMenuRoot description
+//
+// createTime: 2023-07-26 16:31:12
+//
+// author: hailaz
+func (doc *DocDownload) GetMenuRoot(selector string) (*rod.Element, error) {
+	// 菜单页面在整个解析过程中都会使用，不受PageTimeout限制
+	page, err := doc.openPage(doc.MainURL, 0)
+	if err != nil {
+		return nil, err
+	}
+	page.SetWindow(&proto.BrowserBounds{WindowState: proto.BrowserWindowStateMaximized})
+	page.SetViewport(&proto.EmulationSetDeviceMetricsOverride{
+		Width:  1920,
+		Height: 100000,
+	})
+	return page.Element(selector)
+}
+
+// OpenPage 打开页面并等待页面稳定，页面受任务context和PageTimeout控制，使用后需调用 ClosePage 关闭
+//
+// createTime: 2026-10-18 09:07:09
+func (doc *DocDownload) OpenPage(pageURL string) (*rod.Page, error) {
+	return doc.openPage(pageURL, doc.PageTimeout)
+}
+
+// openPage description
+//
+// createTime: 2026-10-18 09:08:35
+func (doc *DocDownload) openPage(pageURL string, timeout time.Duration) (*rod.Page, error) {
+	page, err := doc.newPage()
+	if err != nil {
+		return nil, err
+	}
+	if timeout > 0 {
+		page = page.Timeout(timeout)
+	}
+	if err := page.Navigate(pageURL); err != nil {
+		doc.closePage(page, timeout)
+		return nil, err
+	}
+	if err := page.WaitStable(time.Second); err != nil {
+		doc.closePage(page, timeout)
+		return nil, err
+	}
+	return page, nil
+}
+
+// ClosePage 关闭 OpenPage 打开的页面
+//
+// createTime: 2026-10-18 09:08:35
+func (doc *DocDownload) ClosePage(page *rod.Page) error {
+	return doc.closePage(page, doc.PageTimeout)
+}
+
+// closePage description
+//
+// createTime: 2026-10-18 09:08:35
+func (doc *DocDownload) closePage(page *rod.Page, timeout time.Duration) error {
+	if timeout > 0 {
+		page = page.CancelTimeout()
+	}
+	// 渲染阶段页面放回页面池复用
+	if doc.pagePool != nil {
+		doc.pagePool.Put(page)
+		return nil
+	}
+	// 任务取消后仍需关闭页面，避免标签页泄漏
+	return page.Context(context.Background()).Close()
+}
+
+// newPage 创建空白页面，渲染阶段从页面池获取
+//
+// createTime: 2026-10-18 09:10:44
+func (doc *DocDownload) newPage() (*rod.Page, error) {
+	create := func() (*rod.Page, error) {
+		page, err := doc.browser.Context(doc.ctx).Page(proto.TargetCreateTarget{})
+		if err != nil || len(doc.headers) == 0 {
+			return page, err
+		}
+		if err := doc.hostHeaders(page); err != nil {
+			page.Close()
+			return nil, err
+		}
+		return page, nil
+	}
+	if doc.pagePool == nil {
+		return create()
+	}
+	page, err := doc.pagePool.Get(create)
+	if err != nil {
+		// 归还名额
+		doc.pagePool.Put(nil)
+		return nil, err
+	}
+	return page, nil
+}
+
+// SavePDFPage 保存pdf并返回页数，结果会记录到汇总中
+//
+// createTime: 2026-10-18 09:07:09
+func (doc *DocDownload) SavePDFPage(filePath string, pageURL string) (int, error) {
+	// 任务已取消，不再记录失败
+	if err := doc.ctx.Err(); err != nil {
+		return 0, err
+	}
+	err := doc.SavePDF(filePath, pageURL)
+	if err != nil {
+		doc.AddResult(pageURL, filePath, err)
+		return 0, err
+	}
+	page, err := api.PageCountFile(filePath)
+	if err != nil {
+		err = NewPageError(StagePDF, pageURL, filePath, err)
+		doc.AddResult(pageURL, filePath, err)
+		return 0, err
+	}
+	doc.AddResult(pageURL, filePath, nil)
+	return page, nil
+}
+
+// Index description
+//
+// createTime: 2023-07-28 14:42:42
+//
+// author: hailaz
+func (doc *DocDownload) Index() *DocNode {
+	dirPath := doc.OutputDir()
+	text := "首页"
+
+	if doc.Mode == DocDownloadModePDF {
+		fileName := fmt.Sprintf("%s.pdf", text)
+		return &DocNode{
+			Title: text,
+			URL:   doc.MainURL,
+			Path:  path.Join(dirPath, fileName),
+		}
+	}
+	fileNameMD := fmt.Sprintf("%s.md", text)
+	filePath := path.Join(dirPath, fileNameMD)
+	filePath = strings.ReplaceAll(filePath, "(🔥重点🔥)", "")
+	filePath = strings.ReplaceAll(filePath, "🔥", "")
+	filePath = strings.ReplaceAll(filePath, "(", "-")
+	filePath = strings.ReplaceAll(filePath, ")", "")
+	return &DocNode{
+		Title: text,
+		URL:   doc.MainURL,
+		Path:  filePath,
+	}
+}
+
+// SavePDF description
+//
+// createTime: 2023-07-11 16:51:31
+//
+// author: hailaz
+func (doc *DocDownload) SavePDF(filePath string, pageUrl string) error {
+	// log.Println("SavePDF", filePath)
+	dir := path.Dir(filePath)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		log.Println("创建目录", dir)
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return NewPageError(StagePDF, pageUrl, filePath, err)
+		}
+	}
+	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+		page, err := doc.OpenPage(pageUrl)
+		if err != nil {
+			return NewPageError(StagePage, pageUrl, filePath, err)
+		}
+		defer doc.ClosePage(page)
+		if err := doc.Adapter.PreparePage(doc, page); err != nil {
+			return NewPageError(StagePage, pageUrl, filePath, err)
+		}
+		if err := PageToPDFWithOptions(page, filePath, doc.Adapter.PrintOptions()); err != nil {
+			return NewPageError(StagePDF, pageUrl, filePath, err)
+		}
+	}
+	return nil
+}
+
+// SaveMD description
+//
+// createTime: 2023-07-11 16:51:31
+//
+// author: hailaz
+func (doc *DocDownload) SaveMD(filePath string, pageUrl string) error {
+	dir := path.Dir(filePath)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		fmt.Println("创建目录", dir)
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return NewPageError(StageMarkdown, pageUrl, filePath, err)
+		}
+	}
+
+	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+		if err := doc.Adapter.PageToMD(doc, filePath, pageUrl); err != nil {
+			if _, ok := err.(*PageError); ok {
+				return err
+			}
+			return NewPageError(StageMarkdown, pageUrl, filePath, err)
+		}
+	}
+	return nil
+}
+
+// PageToPDF description
+//
+// createTime: 2023-07-28 16:45:39
+//
+// author: hailaz
+func PageToPDF(page *rod.Page, filePath string) error {
+	return PageToPDFWithCfg(page, filePath, &proto.PagePrintToPDF{})
+}
+
+// PageToPDFWithCfg description
+//
+// createTime: 2023-08-03 11:02:25
+//
+// author: hailaz
+func PageToPDFWithCfg(page *rod.Page, filePath string, req *proto.PagePrintToPDF) error {
+	r, err := page.PDF(req)
+	if err != nil {
+		log.Printf("PDF[err]: %s", err)
+		return err
+	}
+	bin, err := io.ReadAll(r)
+	if err != nil {
+		log.Printf("ReadAll[err]: %s", err)
+		return err
+	}
+	return utils.OutputFile(filePath, bin)
+}
+
+// PageToPDFWithOptions 按打印参数保存pdf，opt为nil时使用默认参数
+//
+// createTime: 2026-10-18 09:05:00
+func PageToPDFWithOptions(page *rod.Page, filePath string, opt *PrintOptions) error {
+	if opt == nil {
+		return PageToPDF(page, filePath)
+	}
+	req := &proto.PagePrintToPDF{
+		PrintBackground: true,
+	}
+	if opt.PaperWidth > 0 {
+		width := opt.PaperWidth
+		req.PaperWidth = &width
+	}
+	err := PageToPDFWithCfg(page, filePath, req)
+	if err != nil || !opt.SinglePage {
+		return err
+	}
+	// 获取页数，合并成单页
+	pageCount, err := api.PageCountFile(filePath)
+	if err == nil {
+		pageHeight := opt.PageHeight
+		if pageHeight <= 0 {
+			pageHeight = 11
+		}
+		height := pageHeight * float64(pageCount)
+		req.PaperHeight = &height
+		return PageToPDFWithCfg(page, filePath, req)
+	}
+	return nil
+}
+
+// RemoveElements 删除页面中匹配选择器的元素
+//
+// createTime: 2026-10-18 09:23:12
+func RemoveElements(page *rod.Page, selectors ...string) error {
+	_, err := page.Eval(`(selectors) => {
+		selectors.forEach(selector => {
+			document.querySelectorAll(selector).forEach(element => element.remove());
+		});
+	}`, selectors)
+	return err
+}
+
+// AddStyle 向页面添加样式
+//
+// createTime: 2026-10-18 09:23:12
+func AddStyle(page *rod.Page, css string) error {
+	_, err := page.Eval(`(css) => {
+		const style = document.createElement('style');
+		style.textContent = css;
+		document.head.appendChild(style);
+	}`, css)
+	return err
+}
+
+// ScrollToBottom 平滑滚动到页面底部，触发懒加载的内容
+//
+// createTime: 2026-10-18 09:23:12
+func ScrollToBottom(page *rod.Page) error {
+	_, err := page.Eval(`() => {
+		return new Promise((resolve) => {
+			const totalHeight = document.documentElement.scrollHeight;
+			let currentPosition = 0;
+			const step = 300; // 每次滚动300像素
+			const delay = 300; // 每次滚动间隔ms
+			
+			const smoothScroll = () => {
+				if (currentPosition < totalHeight) {
+					currentPosition = Math.min(currentPosition + step, totalHeight);
+					window.scrollTo(0, currentPosition);
+					setTimeout(smoothScroll, delay);
+				} else {
+					resolve();
+				}
+			};
+			
+			smoothScroll();
+		});
+	}`)
+	return err
+}
+
+// WaitImages 等待页面中的图片加载完成，超时只记录日志
+//
+// createTime: 2026-10-18 09:23:12
+func WaitImages(page *rod.Page, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(page.GetContext(), timeout)
+	defer cancel()
+
+	err := utils.Retry(ctx,
+		utils.BackoffSleeper(
+			100*time.Millisecond, // 初始等待
+			2*time.Second,        // 最大等待2s
+			nil,                  // 使用默认退避算法
+		),
+		func() (stop bool, err error) {
+			res, err := page.Eval(`() => {
+				const images = document.querySelectorAll('img');
+				for (let i = 0; i < images.length; i++) {
+					if (!images[i].complete || images[i].naturalWidth === 0) {
+						return false;
+					}
+				}
+				return true;
+            	}`)
+			if err != nil {
+				return true, err
+			}
+			allLoaded := res.Value.Bool()
+			if allLoaded {
+				return true, nil
+			}
+			log.Println("图片未加载完成，继续等待...")
+			return false, nil // 继续重试
+		})
+
+	if err != nil {
+		log.Printf("等待图片渲染完成时出错: %v\n", err)
+	} else {
+		log.Println("图片已渲染完成")
+	}
+}
+
+// Move 移动文件
+//
+// createTime: 2023-07-27 15:07:55
+//
+// author: hailaz
+func (doc *DocDownload) Move(targetDir string) error {
+	if _, err := os.Stat(targetDir); os.IsNotExist(err) {
+		fmt.Println("创建目录", targetDir)
+		if err := os.MkdirAll(targetDir, os.ModePerm); err != nil {
+			return err
+		}
+	}
+
+	if len(doc.SplitFiles) > 0 {
+		for _, file := range doc.SplitFiles {
+			src := file
+			dst := path.Join(targetDir, path.Base(src))
+			if _, err := os.Stat(src); os.IsNotExist(err) {
+				continue
+			}
+			// 复制文件
+			os.Rename(src, dst)
+
+		}
+	}
+
+	src := doc.OutputPDF()
+	dst := path.Join(targetDir, path.Base(src))
+
+	if _, err := os.Stat(src); os.IsNotExist(err) {
+		return err
+	}
+	// 复制文件
+	return os.Rename(src, dst)
+
+}
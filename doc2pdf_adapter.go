@@ -0,0 +1,266 @@
+package doc2pdf
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/go-rod/rod"
+)
+
+// SiteAdapter 站点适配器，每种文档平台实现一个适配器
+type SiteAdapter interface {
+	// Name 适配器名称，命令行通过名称选择适配器
+	Name() string
+	// Brief 适配器简介
+	Brief() string
+	// Options 适配器支持的额外参数
+	Options() []AdapterOption
+	// SetOption 设置额外参数
+	SetOption(name string, value string) error
+	// Init 任务开始前初始化，可调整下载参数，调用时尚未连接浏览器
+	Init(doc *DocDownload) error
+	// MenuRootSelector 菜单根节点选择器，为空时不打开菜单页，ParseMenu的root为nil
+	MenuRootSelector() string
+	// ParseMenu 解析菜单，返回root下按菜单顺序排列的文档节点，渲染由导出器根据文档树完成。
+	// 菜单无法读取时返回错误，任务失败
+	ParseMenu(doc *DocDownload, root *rod.Element, level int, dirPath string) ([]*DocNode, error)
+	// PreparePage 打印前处理页面
+	PreparePage(doc *DocDownload, page *rod.Page) error
+	// PrintOptions 打印参数，返回nil时使用浏览器默认参数
+	PrintOptions() *PrintOptions
+	// MarkdownOptions markdown模式下的正文根节点和需要删除的元素
+	MarkdownOptions() *MarkdownOptions
+	// PageToMD 提取页面markdown
+	PageToMD(doc *DocDownload, filePath string, pageURL string) error
+	// Fingerprint 页面内容指纹，指纹不变时增量导出不会重新渲染该页面
+	Fingerprint(doc *DocDownload, pageURL string) (string, error)
+	// Exporter 适配器自定义的导出器，返回nil时使用当前模式的默认导出器
+	Exporter(doc *DocDownload) Exporter
+	// CoverTemplate 封面html模板，为空时使用默认模板，数据为 CoverData
+	CoverTemplate() string
+	// Finish 任务结束后处理
+	Finish(doc *DocDownload) error
+}
+
+// AdapterOption 适配器额外参数
+type AdapterOption struct {
+	Name   string // 参数名
+	Short  string // 短参数名
+	Brief  string // 参数说明
+	Orphan bool   // 是否为开关参数，出现即为true
+}
+
+// ParseBoolOption 解析开关参数的值，支持true、false、1、0等写法，值错误时返回错误
+//
+// createTime: 2026-10-18 09:05:00
+func ParseBoolOption(name string, value string) (bool, error) {
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("参数%s的值错误: %s", name, value)
+	}
+	return b, nil
+}
+
+// PrintOptions 打印参数
+type PrintOptions struct {
+	PaperWidth float64 `json:"paperWidth" yaml:"paperWidth"` // 纸张宽度，单位英寸，0为默认
+	PageHeight float64 `json:"pageHeight" yaml:"pageHeight"` // 单页高度，单位英寸，SinglePage为true时使用
+	SinglePage bool    `json:"singlePage" yaml:"singlePage"` // 是否将页面打印为一个长页
+}
+
+// MarkdownOptions markdown提取参数
+type MarkdownOptions struct {
+	ContentSelector string   // 正文根节点选择器
+	Strip           []string // 转换前需要删除的元素选择器
+}
+
+// AdapterFactory 适配器构造函数
+type AdapterFactory func() SiteAdapter
+
+var (
+	adapterMu sync.RWMutex
+	adapters  = make(map[string]AdapterFactory)
+)
+
+// RegisterAdapter 注册适配器，同名适配器会被覆盖
+//
+// createTime: 2026-10-18 09:05:00
+func RegisterAdapter(name string, factory AdapterFactory) {
+	adapterMu.Lock()
+	defer adapterMu.Unlock()
+	adapters[name] = factory
+}
+
+// NewAdapter 根据名称创建适配器
+//
+// createTime: 2026-10-18 09:05:00
+func NewAdapter(name string) (SiteAdapter, error) {
+	adapterMu.RLock()
+	factory, ok := adapters[name]
+	adapterMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("适配器不存在: %s", name)
+	}
+	return factory(), nil
+}
+
+// AdapterNames 返回已注册的适配器名称，按名称排序
+//
+// createTime: 2026-10-18 09:05:00
+func AdapterNames() []string {
+	adapterMu.RLock()
+	defer adapterMu.RUnlock()
+	names := make([]string, 0, len(adapters))
+	for name := range adapters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DownloadWithAdapter 使用适配器下载文档，返回下载结果汇总
+//
+// createTime: 2026-10-18 09:05:00
+func DownloadWithAdapter(ctx context.Context, adapter SiteAdapter, mainURL string, outputDir string, mode string, opts ...DocOption) (*RunSummary, error) {
+	doc, err := newAdapterDoc(adapter, mainURL, outputDir, mode, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := doc.Start(ctx); err != nil {
+		return doc.Summary(), err
+	}
+	if err := adapter.Finish(doc); err != nil {
+		return doc.Summary(), NewPageError(StageFinish, mainURL, "", err)
+	}
+	log.Println("下载完成", doc.Summary())
+	return doc.Summary(), nil
+}
+
+// DiscoverWithAdapter 使用适配器解析菜单，只返回文档树不渲染页面
+//
+// createTime: 2026-10-18 09:15:17
+func DiscoverWithAdapter(ctx context.Context, adapter SiteAdapter, mainURL string, outputDir string, mode string, opts ...DocOption) (*DocTree, error) {
+	doc, err := newAdapterDoc(adapter, mainURL, outputDir, mode, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer doc.Close()
+	if doc.RunTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, doc.RunTimeout)
+		defer cancel()
+	}
+	doc.ctx = ctx
+	if err := doc.authenticate(); err != nil {
+		return nil, NewPageError(StageAuth, mainURL, "", err)
+	}
+	return doc.Discover(ctx)
+}
+
+// newAdapterDoc 创建使用适配器的下载任务，适配器在Init中设置默认参数，
+// 调用方参数在Init之后、连接浏览器之前应用一次，优先于适配器默认参数
+//
+// createTime: 2026-10-18 09:15:17
+func newAdapterDoc(adapter SiteAdapter, mainURL string, outputDir string, mode string, opts ...DocOption) (*DocDownload, error) {
+	doc, err := newDocDownload(mainURL, outputDir)
+	if err != nil {
+		return nil, err
+	}
+	doc.Mode = mode
+	doc.Adapter = adapter
+	if err := adapter.Init(doc); err != nil {
+		return nil, NewPageError(StageInit, mainURL, "", err)
+	}
+	for _, opt := range opts {
+		opt(doc)
+	}
+	if err := doc.connect(); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// BaseAdapter 适配器默认实现，具体适配器可嵌入后按需覆盖
+type BaseAdapter struct{}
+
+// Name description
+func (a *BaseAdapter) Name() string {
+	return ""
+}
+
+// Brief description
+func (a *BaseAdapter) Brief() string {
+	return ""
+}
+
+// Options description
+func (a *BaseAdapter) Options() []AdapterOption {
+	return nil
+}
+
+// SetOption description
+func (a *BaseAdapter) SetOption(name string, value string) error {
+	return fmt.Errorf("不支持的参数: %s", name)
+}
+
+// Init description
+func (a *BaseAdapter) Init(doc *DocDownload) error {
+	return nil
+}
+
+// MenuRootSelector description
+func (a *BaseAdapter) MenuRootSelector() string {
+	return ""
+}
+
+// ParseMenu description
+func (a *BaseAdapter) ParseMenu(doc *DocDownload, root *rod.Element, level int, dirPath string) ([]*DocNode, error) {
+	return nil, nil
+}
+
+// PreparePage description
+func (a *BaseAdapter) PreparePage(doc *DocDownload, page *rod.Page) error {
+	return nil
+}
+
+// PrintOptions description
+func (a *BaseAdapter) PrintOptions() *PrintOptions {
+	return nil
+}
+
+// MarkdownOptions 默认使用整个body作为正文
+func (a *BaseAdapter) MarkdownOptions() *MarkdownOptions {
+	return &MarkdownOptions{ContentSelector: "body"}
+}
+
+// PageToMD description
+func (a *BaseAdapter) PageToMD(doc *DocDownload, filePath string, pageURL string) error {
+	return PageToMD(doc, filePath, pageURL)
+}
+
+// Fingerprint 默认使用响应头中的ETag或Last-Modified，没有时使用markdown正文的hash
+func (a *BaseAdapter) Fingerprint(doc *DocDownload, pageURL string) (string, error) {
+	if fp, err := doc.HeaderFingerprint(pageURL); err == nil && fp != "" {
+		return fp, nil
+	}
+	return doc.ContentFingerprint(pageURL, doc.Adapter.MarkdownOptions().ContentSelector)
+}
+
+// Exporter description
+func (a *BaseAdapter) Exporter(doc *DocDownload) Exporter {
+	return nil
+}
+
+// CoverTemplate description
+func (a *BaseAdapter) CoverTemplate() string {
+	return ""
+}
+
+// Finish description
+func (a *BaseAdapter) Finish(doc *DocDownload) error {
+	return nil
+}
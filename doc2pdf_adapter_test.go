@@ -0,0 +1,92 @@
+package doc2pdf_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hailaz/doc2pdf"
+)
+
+// TestAdapterBoolOptions 所有适配器的开关参数使用同样的规则解析
+//
+// createTime: 2026-10-18 09:05:00
+func TestAdapterBoolOptions(t *testing.T) {
+	cases := map[string]string{
+		"confluence": "space-split",
+		"mdbook":     "no-print",
+		"hugo":       "print",
+		"sphinx":     "next",
+		"sitemap":    "fetch-title",
+	}
+	for name, option := range cases {
+		adapter, err := doc2pdf.NewAdapter(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, value := range []string{"true", "1", "false", "0"} {
+			if err := adapter.SetOption(option, value); err != nil {
+				t.Errorf("%s --%s=%s: %s", name, option, value, err)
+			}
+		}
+		if err := adapter.SetOption(option, "yes"); err == nil {
+			t.Errorf("%s --%s=yes should fail", name, option)
+		}
+	}
+
+	adapter := &doc2pdf.ConfluenceAdapter{}
+	if err := adapter.SetOption("comments", "false"); err != nil || adapter.WithComments {
+		t.Errorf("comments = %v, err = %v", adapter.WithComments, err)
+	}
+	if err := adapter.SetOption("comments", "true"); err != nil || !adapter.WithComments {
+		t.Errorf("comments = %v, err = %v", adapter.WithComments, err)
+	}
+	if v, err := doc2pdf.ParseBoolOption("print", "TRUE"); err != nil || !v {
+		t.Errorf("ParseBoolOption = %v, %v", v, err)
+	}
+}
+
+// TestNewDocDownloadError 入口地址无法解析时在启动浏览器前失败
+//
+// createTime: 2026-10-18 09:07:09
+func TestNewDocDownloadError(t *testing.T) {
+	_, err := doc2pdf.NewDocDownload("://example.com", t.TempDir())
+	var pageErr *doc2pdf.PageError
+	if !errors.As(err, &pageErr) || pageErr.Stage != doc2pdf.StageInit {
+		t.Errorf("err = %v", err)
+	}
+}
+
+// TestAdapterDocOptions 调用方参数在适配器Init之后只应用一次，可覆盖适配器的默认参数
+//
+// createTime: 2026-10-18 10:00:28
+func TestAdapterDocOptions(t *testing.T) {
+	var (
+		calls    int
+		initMain bool
+	)
+	opt := func(doc *doc2pdf.DocDownload) {
+		calls++
+		initMain = doc.IsDownloadMain
+		doc.IsDownloadMain = false
+	}
+	// 连接不上浏览器，任务在应用参数后失败
+	browser := doc2pdf.NewBrowser(doc2pdf.BrowserOptions{ControlURL: "127.0.0.1:1"})
+	defer browser.Close()
+	_, err := doc2pdf.DiscoverWithAdapter(context.Background(), &doc2pdf.SphinxAdapter{}, "https://example.com/docs/", t.TempDir(),
+		doc2pdf.DocDownloadModePDF, doc2pdf.WithBrowser(browser), opt)
+	var pageErr *doc2pdf.PageError
+	if !errors.As(err, &pageErr) || pageErr.Stage != doc2pdf.StageBrowser {
+		t.Fatalf("err = %v", err)
+	}
+	if calls != 1 || !initMain {
+		t.Errorf("calls = %d, IsDownloadMain after Init = %v", calls, initMain)
+	}
+
+	calls = 0
+	_, err = doc2pdf.DiscoverWithAdapter(context.Background(), &doc2pdf.CustomAdapter{}, "https://example.com/docs/", t.TempDir(),
+		doc2pdf.DocDownloadModePDF, doc2pdf.WithBrowser(browser), opt)
+	if !errors.As(err, &pageErr) || pageErr.Stage != doc2pdf.StageInit || calls != 0 {
+		t.Errorf("err = %v, calls = %d", err, calls)
+	}
+}
@@ -0,0 +1,394 @@
+package doc2pdf
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/gogf/gf/v2/encoding/gyaml"
+	"github.com/gogf/gf/v2/os/gfile"
+)
+
+// envVariable 配置中引用环境变量的写法 ${NAME}
+var envVariable = regexp.MustCompile(`\$\{(\w+)\}`)
+
+// AuthConfig 访问需要登录的站点，cookie、http头和登录脚本可以同时使用。
+// 账号、密码和token应写成${NAME}从环境变量读取，这些值不会写入日志和输出文件
+type AuthConfig struct {
+	CookieFile string            `json:"cookieFile" yaml:"cookieFile"` // Netscape(cookies.txt)或json格式的cookie文件
+	Headers    map[string]string `json:"headers" yaml:"headers"`       // 浏览器和直接请求中发送到文档站点的请求附加的http头
+	Login      *LoginScript      `json:"login" yaml:"login"`           // 解析菜单前执行一次的登录脚本
+}
+
+// LoginScript 登录脚本，打开登录页后按顺序执行步骤
+type LoginScript struct {
+	URL   string      `json:"url" yaml:"url"`     // 登录页地址，默认为文档入口地址
+	Steps []LoginStep `json:"steps" yaml:"steps"` // 登录步骤
+}
+
+// LoginStep 登录步骤，每个步骤只设置一种操作
+type LoginStep struct {
+	Fill  string `json:"fill" yaml:"fill"`   // 需要填写的输入框选择器
+	Value string `json:"value" yaml:"value"` // 填写的内容，支持${NAME}
+	Click string `json:"click" yaml:"click"` // 需要点击的元素选择器，点击后等待页面稳定
+	Wait  string `json:"wait" yaml:"wait"`   // 等待出现的元素选择器，用于确认登录成功
+}
+
+// LoadAuthConfig 读取yaml或json格式的登录配置
+//
+// createTime: 2026-10-18 09:58:13
+func LoadAuthConfig(file string) (*AuthConfig, error) {
+	if !gfile.Exists(file) {
+		return nil, fmt.Errorf("配置文件不存在: %s", file)
+	}
+	cfg := &AuthConfig{}
+	if err := gyaml.DecodeTo(gfile.GetBytes(file), cfg); err != nil {
+		return nil, fmt.Errorf("配置文件格式错误 %s: %w", file, err)
+	}
+	// cookie文件相对于配置文件所在目录
+	if cfg.CookieFile != "" && !filepath.IsAbs(cfg.CookieFile) {
+		cfg.CookieFile = filepath.Join(filepath.Dir(file), cfg.CookieFile)
+	}
+	if cfg.Login != nil {
+		for i, step := range cfg.Login.Steps {
+			if step.Fill == "" && step.Click == "" && step.Wait == "" {
+				return nil, fmt.Errorf("登录脚本第%d步缺少fill、click或wait: %s", i+1, file)
+			}
+		}
+	}
+	return cfg, nil
+}
+
+// WithAuth 设置登录配置，在解析菜单前执行
+//
+// createTime: 2026-10-18 09:58:13
+func WithAuth(auth *AuthConfig) DocOption {
+	return func(doc *DocDownload) {
+		doc.Auth = auth
+	}
+}
+
+// ExpandEnv 将${NAME}替换为环境变量的值，环境变量未设置时返回错误，错误中只包含变量名
+//
+// createTime: 2026-10-18 09:58:13
+func ExpandEnv(s string) (string, error) {
+	var missing []string
+	expanded := envVariable.ReplaceAllStringFunc(s, func(m string) string {
+		name := envVariable.FindStringSubmatch(m)[1]
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			missing = append(missing, name)
+		}
+		return value
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("环境变量未设置: %s", strings.Join(missing, ","))
+	}
+	return expanded, nil
+}
+
+// LoadCookieFile 读取cookie文件，以[开头的为json数组(浏览器插件或CDP导出的格式)，否则按Netscape格式解析
+//
+// createTime: 2026-10-18 09:58:13
+func LoadCookieFile(file string) ([]*proto.NetworkCookieParam, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "[") {
+		return parseJSONCookies(data)
+	}
+	return parseNetscapeCookies(string(data))
+}
+
+// jsonCookie 兼容浏览器插件(expirationDate)和CDP(expires)导出的字段
+type jsonCookie struct {
+	Name           string  `json:"name"`
+	Value          string  `json:"value"`
+	Domain         string  `json:"domain"`
+	Path           string  `json:"path"`
+	Secure         bool    `json:"secure"`
+	HTTPOnly       bool    `json:"httpOnly"`
+	Expires        float64 `json:"expires"`
+	ExpirationDate float64 `json:"expirationDate"`
+}
+
+// parseJSONCookies description
+//
+// createTime: 2026-10-18 09:58:13
+func parseJSONCookies(data []byte) ([]*proto.NetworkCookieParam, error) {
+	var items []jsonCookie
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("cookie文件格式错误: %w", err)
+	}
+	cookies := make([]*proto.NetworkCookieParam, 0, len(items))
+	for _, item := range items {
+		if item.Name == "" || item.Domain == "" {
+			continue
+		}
+		expires := item.Expires
+		if expires <= 0 {
+			expires = item.ExpirationDate
+		}
+		cookies = append(cookies, &proto.NetworkCookieParam{
+			Name:     item.Name,
+			Value:    item.Value,
+			Domain:   item.Domain,
+			Path:     cookiePath(item.Path),
+			Secure:   item.Secure,
+			HTTPOnly: item.HTTPOnly,
+			Expires:  proto.TimeSinceEpoch(max(expires, 0)),
+		})
+	}
+	return cookies, nil
+}
+
+// parseNetscapeCookies 解析curl、wget和浏览器插件导出的cookies.txt，
+// 每行为 domain flag path secure expiration name value，#HttpOnly_前缀表示HttpOnly
+//
+// createTime: 2026-10-18 09:58:13
+func parseNetscapeCookies(content string) ([]*proto.NetworkCookieParam, error) {
+	cookies := make([]*proto.NetworkCookieParam, 0)
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		httpOnly := false
+		if strings.HasPrefix(line, "#HttpOnly_") {
+			httpOnly = true
+			line = strings.TrimPrefix(line, "#HttpOnly_")
+		}
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) < 7 {
+			// 不输出行内容，避免泄漏cookie
+			return nil, fmt.Errorf("cookie文件第%d行格式错误", lineNo)
+		}
+		expires, err := strconv.ParseFloat(fields[4], 64)
+		if err != nil {
+			return nil, fmt.Errorf("cookie文件第%d行过期时间错误", lineNo)
+		}
+		cookies = append(cookies, &proto.NetworkCookieParam{
+			Domain:   fields[0],
+			Path:     cookiePath(fields[2]),
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+			Expires:  proto.TimeSinceEpoch(max(expires, 0)),
+			Name:     fields[5],
+			Value:    strings.Join(fields[6:], "\t"),
+			HTTPOnly: httpOnly,
+		})
+	}
+	return cookies, scanner.Err()
+}
+
+// cookiePath description
+func cookiePath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	return p
+}
+
+// authenticate 加载cookie、设置http头并执行登录脚本，完成后将浏览器的cookie同步到直接请求使用的客户端
+//
+// createTime: 2026-10-18 09:58:13
+func (doc *DocDownload) authenticate() error {
+	if doc.Auth == nil {
+		return nil
+	}
+	auth := doc.Auth
+	headers := make([]string, 0, len(auth.Headers)*2)
+	for name, value := range auth.Headers {
+		value, err := ExpandEnv(value)
+		if err != nil {
+			return fmt.Errorf("http头%s: %w", name, err)
+		}
+		headers = append(headers, name, value)
+	}
+	doc.headers = headers
+	if auth.CookieFile != "" {
+		cookies, err := LoadCookieFile(auth.CookieFile)
+		if err != nil {
+			return err
+		}
+		if err := doc.browser.SetCookies(cookies); err != nil {
+			return err
+		}
+		log.Printf("已加载%d个cookie: %s", len(cookies), auth.CookieFile)
+	}
+	if auth.Login != nil {
+		if err := doc.login(auth.Login); err != nil {
+			return err
+		}
+	}
+	return doc.syncCookies()
+}
+
+// login 执行登录脚本，日志中只记录选择器
+//
+// createTime: 2026-10-18 09:58:13
+func (doc *DocDownload) login(script *LoginScript) error {
+	loginURL := script.URL
+	if loginURL == "" {
+		loginURL = doc.MainURL
+	}
+	log.Println("执行登录脚本", loginURL)
+	page, err := doc.OpenPage(loginURL)
+	if err != nil {
+		return err
+	}
+	defer doc.ClosePage(page)
+	for i, step := range script.Steps {
+		if err := loginStep(page, step); err != nil {
+			return fmt.Errorf("登录脚本第%d步失败: %w", i+1, err)
+		}
+	}
+	return page.WaitStable(time.Second)
+}
+
+// loginStep description
+//
+// createTime: 2026-10-18 09:58:13
+func loginStep(page *rod.Page, step LoginStep) error {
+	switch {
+	case step.Fill != "":
+		log.Println("填写", step.Fill)
+		value, err := ExpandEnv(step.Value)
+		if err != nil {
+			return err
+		}
+		el, err := page.Element(step.Fill)
+		if err != nil {
+			return err
+		}
+		if err := el.SelectAllText(); err != nil {
+			return err
+		}
+		return el.Input(value)
+	case step.Click != "":
+		log.Println("点击", step.Click)
+		el, err := page.Element(step.Click)
+		if err != nil {
+			return err
+		}
+		if err := el.Click(proto.InputMouseButtonLeft, 1); err != nil {
+			return err
+		}
+		return page.WaitStable(time.Second)
+	case step.Wait != "":
+		log.Println("等待", step.Wait)
+		_, err := page.Element(step.Wait)
+		return err
+	}
+	return nil
+}
+
+// syncCookies 将浏览器的cookie复制到直接请求使用的客户端，http头只发送到文档站点
+//
+// createTime: 2026-10-18 09:58:13
+func (doc *DocDownload) syncCookies() error {
+	cookies, err := doc.browser.GetCookies()
+	if err != nil {
+		return err
+	}
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return err
+	}
+	for _, c := range cookies {
+		scheme := "http"
+		if c.Secure {
+			scheme = "https"
+		}
+		u := &url.URL{Scheme: scheme, Host: strings.TrimPrefix(c.Domain, "."), Path: c.Path}
+		jar.SetCookies(u, []*http.Cookie{{
+			Name:     c.Name,
+			Value:    c.Value,
+			Domain:   c.Domain,
+			Path:     c.Path,
+			Secure:   c.Secure,
+			HttpOnly: c.HTTPOnly,
+		}})
+	}
+	header := http.Header{}
+	for i := 0; i+1 < len(doc.headers); i += 2 {
+		header.Set(doc.headers[i], doc.headers[i+1])
+	}
+	host := ""
+	if u, err := url.Parse(doc.baseURL); err == nil {
+		host = u.Host
+	}
+	doc.httpClient = &http.Client{
+		Jar:       jar,
+		Transport: &headerTransport{base: http.DefaultTransport, host: host, header: header},
+	}
+	return nil
+}
+
+// hostHeaders 拦截浏览器发送到文档站点的请求附加http头，图片、统计等第三方地址的请求不经过拦截，
+// 避免token泄漏。页面关闭后停止拦截
+//
+// createTime: 2026-10-18 09:58:13
+func (doc *DocDownload) hostHeaders(page *rod.Page) error {
+	ctx, cancel := context.WithCancel(doc.ctx)
+	router := page.Context(ctx).HijackRequests()
+	err := router.Add(doc.baseURL+"/*", "", func(h *rod.Hijack) {
+		headers := make([]*proto.FetchHeaderEntry, 0, len(doc.headers)/2+len(h.Request.Headers()))
+		override := make(map[string]bool)
+		for i := 0; i+1 < len(doc.headers); i += 2 {
+			override[strings.ToLower(doc.headers[i])] = true
+			headers = append(headers, &proto.FetchHeaderEntry{Name: doc.headers[i], Value: doc.headers[i+1]})
+		}
+		for name, value := range h.Request.Headers() {
+			if !override[strings.ToLower(name)] {
+				headers = append(headers, &proto.FetchHeaderEntry{Name: name, Value: value.String()})
+			}
+		}
+		h.ContinueRequest(&proto.FetchContinueRequest{Headers: headers})
+	})
+	if err != nil {
+		cancel()
+		return err
+	}
+	go router.Run()
+	go func() {
+		doc.browser.Context(ctx).EachEvent(func(e *proto.TargetTargetDestroyed) bool {
+			return e.TargetID == page.TargetID
+		})()
+		cancel()
+	}()
+	return nil
+}
+
+// headerTransport 只给文档站点的请求附加http头，避免token发送到图片等第三方地址
+type headerTransport struct {
+	base   http.RoundTripper
+	host   string
+	header http.Header
+}
+
+// RoundTrip description
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if len(t.header) == 0 || req.URL.Host != t.host {
+		return t.base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	for name, values := range t.header {
+		req.Header[name] = values
+	}
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,168 @@
+package doc2pdf_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/hailaz/doc2pdf"
+)
+
+// TestLoadCookieFile description
+//
+// createTime: 2026-10-18 09:58:13
+func TestLoadCookieFile(t *testing.T) {
+	dir := t.TempDir()
+	netscape := filepath.Join(dir, "cookies.txt")
+	os.WriteFile(netscape, []byte(strings.Join([]string{
+		"# Netscape HTTP Cookie File",
+		"",
+		".example.com\tTRUE\t/\tTRUE\t1893456000\tsession\tabc\tdef",
+		"#HttpOnly_docs.example.com\tFALSE\t/wiki\tFALSE\t0\tJSESSIONID\t123",
+	}, "\n")), 0o644)
+	cookies, err := doc2pdf.LoadCookieFile(netscape)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cookies) != 2 {
+		t.Fatalf("cookies = %d", len(cookies))
+	}
+	if c := cookies[0]; c.Domain != ".example.com" || c.Name != "session" || c.Value != "abc\tdef" || !c.Secure || c.Expires != 1893456000 {
+		t.Errorf("unexpected cookie %+v", c)
+	}
+	if c := cookies[1]; c.Domain != "docs.example.com" || c.Path != "/wiki" || !c.HTTPOnly || c.Expires != 0 {
+		t.Errorf("unexpected cookie %+v", c)
+	}
+
+	jsonFile := filepath.Join(dir, "cookies.json")
+	os.WriteFile(jsonFile, []byte(`[
+		{"name": "token", "value": "xyz", "domain": "example.com", "httpOnly": true, "expirationDate": 1893456000.5},
+		{"name": "", "value": "ignored", "domain": "example.com"}
+	]`), 0o644)
+	cookies, err = doc2pdf.LoadCookieFile(jsonFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cookies) != 1 || cookies[0].Name != "token" || cookies[0].Path != "/" || !cookies[0].HTTPOnly || cookies[0].Expires != 1893456000.5 {
+		t.Errorf("unexpected cookies %+v", cookies)
+	}
+
+	bad := filepath.Join(dir, "bad.txt")
+	os.WriteFile(bad, []byte("example.com\tsecret-value"), 0o644)
+	if _, err := doc2pdf.LoadCookieFile(bad); err == nil || strings.Contains(err.Error(), "secret-value") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+// TestExpandEnv description
+//
+// createTime: 2026-10-18 09:58:13
+func TestExpandEnv(t *testing.T) {
+	t.Setenv("DOC2PDF_TEST_TOKEN", "s3cr$t")
+	got, err := doc2pdf.ExpandEnv("Bearer ${DOC2PDF_TEST_TOKEN} $HOME")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "Bearer s3cr$t $HOME" {
+		t.Errorf("got %s", got)
+	}
+	if _, err := doc2pdf.ExpandEnv("${DOC2PDF_TEST_MISSING}"); err == nil || !strings.Contains(err.Error(), "DOC2PDF_TEST_MISSING") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+// TestLoadAuthConfig description
+//
+// createTime: 2026-10-18 09:58:13
+func TestLoadAuthConfig(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "auth.yaml")
+	os.WriteFile(file, []byte(`
+cookieFile: cookies.txt
+headers:
+  Authorization: Bearer ${DOCS_TOKEN}
+login:
+  url: https://sso.example.com/login
+  steps:
+    - fill: "#username"
+      value: ${SSO_USER}
+    - fill: "#password"
+      value: ${SSO_PASSWORD}
+    - click: button[type=submit]
+    - wait: "#main"
+`), 0o644)
+	cfg, err := doc2pdf.LoadAuthConfig(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.CookieFile != filepath.Join(dir, "cookies.txt") || cfg.Headers["Authorization"] != "Bearer ${DOCS_TOKEN}" {
+		t.Errorf("unexpected config %+v", cfg)
+	}
+	if cfg.Login == nil || cfg.Login.URL != "https://sso.example.com/login" || len(cfg.Login.Steps) != 4 {
+		t.Fatalf("unexpected login %+v", cfg.Login)
+	}
+	if step := cfg.Login.Steps[1]; step.Fill != "#password" || step.Value != "${SSO_PASSWORD}" {
+		t.Errorf("unexpected step %+v", step)
+	}
+
+	os.WriteFile(file, []byte("login:\n  steps:\n    - value: x\n"), 0o644)
+	if _, err := doc2pdf.LoadAuthConfig(file); err == nil {
+		t.Error("step without action should fail")
+	}
+}
+
+// TestAuthHeadersHost 浏览器只给文档站点的请求附加http头，第三方图片地址收不到token
+//
+// createTime: 2026-10-18 09:58:13
+func TestAuthHeadersHost(t *testing.T) {
+	if _, exists := launcher.LookPath(); !exists {
+		t.Skip("未找到浏览器")
+	}
+	var (
+		mu     sync.Mutex
+		tokens = make(map[string]string)
+	)
+	record := func(name string, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		tokens[name] = r.Header.Get("Authorization")
+	}
+	thirdParty := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		record("third-party", r)
+		w.Header().Set("Content-Type", "image/svg+xml")
+		fmt.Fprint(w, `<svg xmlns="http://www.w3.org/2000/svg" width="1" height="1"></svg>`)
+	}))
+	defer thirdParty.Close()
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		record(r.URL.Path, r)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprintf(w, `<html><body><h1>Docs</h1><img src="%s/logo.svg"></body></html>`, thirdParty.URL)
+	}))
+	defer site.Close()
+
+	auth := &doc2pdf.AuthConfig{Headers: map[string]string{"Authorization": "Bearer ${DOC2PDF_TEST_TOKEN}"}}
+	t.Setenv("DOC2PDF_TEST_TOKEN", "secret")
+	doc, err := doc2pdf.NewDocDownload(site.URL+"/", filepath.Join(t.TempDir(), "out"), doc2pdf.WithAuth(auth))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.IsDownloadMain = true
+	if err := doc.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if tokens["/"] != "Bearer secret" {
+		t.Errorf("doc site token = %q", tokens["/"])
+	}
+	if token, ok := tokens["third-party"]; !ok || token != "" {
+		t.Errorf("third-party requested = %v, token = %q", ok, token)
+	}
+}
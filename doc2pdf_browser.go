@@ -0,0 +1,153 @@
+package doc2pdf
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/cdp"
+	"github.com/go-rod/rod/lib/launcher"
+)
+
+// BrowserOptions 浏览器参数
+type BrowserOptions struct {
+	ControlURL string // 已运行的浏览器的DevTools地址，如 ws://127.0.0.1:9222/devtools/browser/xxx 或 127.0.0.1:9222，设置后不启动浏览器
+	Bin        string // 浏览器路径，为空时自动查找
+	Headful    bool   // 显示浏览器窗口，用于调试
+}
+
+// Browser 浏览器，第一次使用时启动或连接，可在多个下载任务间共享。
+// 每个任务使用独立的浏览器上下文，cookie互不影响，任务结束时只关闭自己的上下文，
+// 共享的浏览器在全部任务结束后调用Close关闭
+type Browser struct {
+	Options BrowserOptions
+
+	mu       sync.Mutex
+	browser  *rod.Browser
+	ws       *cdp.WebSocket     // DevTools连接，关闭时断开
+	launcher *launcher.Launcher // 本进程启动的浏览器，连接已运行的浏览器时为nil
+}
+
+// NewBrowser 创建浏览器，不会立即启动
+//
+// createTime: 2026-10-18 10:00:28
+func NewBrowser(opts BrowserOptions) *Browser {
+	return &Browser{Options: opts}
+}
+
+// WithBrowser 使用共享的浏览器，任务结束后不会关闭该浏览器
+//
+// createTime: 2026-10-18 10:00:28
+func WithBrowser(b *Browser) DocOption {
+	return func(doc *DocDownload) {
+		doc.sharedBrowser = b
+	}
+}
+
+// WithBrowserOptions 设置任务自己启动或连接的浏览器参数，使用WithBrowser时无效
+//
+// createTime: 2026-10-18 10:00:28
+func WithBrowserOptions(opts BrowserOptions) DocOption {
+	return func(doc *DocDownload) {
+		doc.browserOptions = opts
+	}
+}
+
+// connect 启动或连接浏览器，只执行一次
+//
+// createTime: 2026-10-18 10:00:28
+func (b *Browser) connect() (*rod.Browser, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.browser != nil {
+		return b.browser, nil
+	}
+	controlURL, err := b.controlURL()
+	if err != nil {
+		return nil, err
+	}
+	// 自己建立DevTools连接，关闭时可以只断开连接而不关闭已运行的浏览器
+	ws := &cdp.WebSocket{}
+	if err := ws.Connect(context.Background(), controlURL, nil); err != nil {
+		b.killLauncher()
+		return nil, err
+	}
+	browser := rod.New().Client(cdp.New().Start(ws))
+	if err := browser.Connect(); err != nil {
+		ws.Close()
+		b.killLauncher()
+		return nil, err
+	}
+	b.browser = browser.Trace(b.Options.Headful)
+	b.ws = ws
+	return b.browser, nil
+}
+
+// killLauncher 关闭本进程启动的浏览器
+//
+// createTime: 2026-10-18 10:00:28
+func (b *Browser) killLauncher() {
+	if b.launcher != nil {
+		b.launcher.Kill()
+		b.launcher = nil
+	}
+}
+
+// controlURL 未指定DevTools地址时启动本地浏览器
+//
+// createTime: 2026-10-18 10:00:28
+func (b *Browser) controlURL() (string, error) {
+	if u := b.Options.ControlURL; u != "" {
+		if strings.HasPrefix(u, "ws://") || strings.HasPrefix(u, "wss://") {
+			return u, nil
+		}
+		return launcher.ResolveURL(u)
+	}
+	l := launcher.New().Leakless(false).Headless(!b.Options.Headful)
+	if b.Options.Bin != "" {
+		l.Bin(b.Options.Bin)
+	} else if binPath, exists := launcher.LookPath(); exists {
+		log.Println("找到浏览器", binPath)
+		l.Bin(binPath)
+	}
+	u, err := l.Launch()
+	if err != nil {
+		return "", err
+	}
+	log.Println("浏览器启动成功", u)
+	b.launcher = l
+	return u, nil
+}
+
+// newContext 创建独立的浏览器上下文
+//
+// createTime: 2026-10-18 10:00:28
+func (b *Browser) newContext() (*rod.Browser, error) {
+	browser, err := b.connect()
+	if err != nil {
+		return nil, err
+	}
+	return browser.Incognito()
+}
+
+// Close 关闭本进程启动的浏览器，连接的已运行浏览器只断开连接，浏览器保持运行
+//
+// createTime: 2026-10-18 10:00:28
+func (b *Browser) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.browser == nil {
+		return nil
+	}
+	var err error
+	if b.launcher != nil {
+		err = b.browser.Close()
+		b.killLauncher()
+	}
+	b.ws.Close()
+	b.browser = nil
+	b.ws = nil
+	return err
+}
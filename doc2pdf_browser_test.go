@@ -0,0 +1,61 @@
+package doc2pdf_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/hailaz/doc2pdf"
+)
+
+// TestNewDocDownloadBrowserURL 连接不上指定的浏览器时返回错误，不会启动本地浏览器
+//
+// createTime: 2026-10-18 10:00:28
+func TestNewDocDownloadBrowserURL(t *testing.T) {
+	browser := doc2pdf.NewBrowser(doc2pdf.BrowserOptions{ControlURL: "127.0.0.1:1"})
+	defer browser.Close()
+	_, err := doc2pdf.NewDocDownload("https://example.com/docs", "./output/temp", doc2pdf.WithBrowser(browser))
+	var pageErr *doc2pdf.PageError
+	if !errors.As(err, &pageErr) || pageErr.Stage != doc2pdf.StageBrowser {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+// TestBrowserCloseRemote 关闭连接的已运行浏览器时只断开连接，浏览器保持运行
+//
+// createTime: 2026-10-18 10:00:28
+func TestBrowserCloseRemote(t *testing.T) {
+	binPath, exists := launcher.LookPath()
+	if !exists {
+		t.Skip("未找到浏览器")
+	}
+	l := launcher.New().Leakless(false).Bin(binPath)
+	controlURL, err := l.Launch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Kill()
+
+	browser := doc2pdf.NewBrowser(doc2pdf.BrowserOptions{ControlURL: controlURL})
+	doc, err := doc2pdf.NewDocDownload("https://example.com/docs", t.TempDir(), doc2pdf.WithBrowser(browser))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.Close()
+	if err := browser.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := browser.Close(); err != nil {
+		t.Errorf("second close: %s", err)
+	}
+
+	remote := rod.New().ControlURL(controlURL)
+	if err := remote.Connect(); err != nil {
+		t.Fatalf("remote browser should keep running: %s", err)
+	}
+	defer remote.Close()
+	if _, err := remote.Version(); err != nil {
+		t.Error(err)
+	}
+}
@@ -1,24 +1,20 @@
 package doc2pdf
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"path"
-	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
-	md "github.com/JohannesKaufmann/html-to-markdown"
-	"github.com/JohannesKaufmann/html-to-markdown/plugin"
-	"github.com/PuerkitoBio/goquery"
 	"github.com/go-rod/rod"
-	"github.com/go-rod/rod/lib/devices"
 	"github.com/go-rod/rod/lib/proto"
-	"github.com/gogf/gf/v2/crypto/gmd5"
 	"github.com/gogf/gf/v2/os/gfile"
 	"github.com/pdfcpu/pdfcpu/pkg/api"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
@@ -48,20 +44,29 @@ var (
 // createTime: 2023-07-28 15:27:17
 //
 // author: hailaz
-func DownloadGoFrameAll(mode string) {
+func DownloadGoFrameAll(ctx context.Context, mode string) error {
 	wg := sync.WaitGroup{}
+	var errs []error
+	// 所有版本共用一个浏览器
+	browser := NewBrowser(BrowserOptions{})
+	defer browser.Close()
 	for ver, main := range versionList {
 		ver, main := ver, main
 		wg.Add(1)
 		func() {
-			DownloadConfluence(main, "./output/goframe-"+ver, mode, false)
+			if _, err := DownloadConfluence(ctx, main, "./output/goframe-"+ver, mode, false, WithBrowser(browser)); err != nil {
+				errs = append(errs, err)
+			}
 			if ver == "latest" {
-				DownloadConfluence(main, "./output/goframe-"+ver, mode, true)
+				if _, err := DownloadConfluence(ctx, main, "./output/goframe-"+ver, mode, true, WithBrowser(browser)); err != nil {
+					errs = append(errs, err)
+				}
 			}
 			wg.Done()
 		}()
 	}
 	wg.Wait()
+	return errors.Join(errs...)
 }
 
 // DownloadGoFrameWithVersion description
@@ -69,12 +74,11 @@ func DownloadGoFrameAll(mode string) {
 // createTime: 2023-08-08 18:34:08
 //
 // author: hailaz
-func DownloadGoFrameWithVersion(version string, mode string) {
+func DownloadGoFrameWithVersion(ctx context.Context, version string, mode string) (*RunSummary, error) {
 	if main, ok := versionList[version]; ok {
-		DownloadConfluence(main, "./output/goframe-"+version, mode, false)
-	} else {
-		log.Printf("版本号不存在")
+		return DownloadConfluence(ctx, main, "./output/goframe-"+version, mode, false)
 	}
+	return nil, fmt.Errorf("版本号不存在: %s", version)
 }
 
 // DownloadGoFrameLatest description
@@ -82,8 +86,8 @@ func DownloadGoFrameWithVersion(version string, mode string) {
 // createTime: 2023-07-28 15:21:19
 //
 // author: hailaz
-func DownloadGoFrameLatest(mode string) {
-	DownloadConfluence("https://goframe.org/display/gf", "./output/goframe-latest", mode, false)
+func DownloadGoFrameLatest(ctx context.Context, mode string) (*RunSummary, error) {
+	return DownloadConfluence(ctx, "https://goframe.org/display/gf", "./output/goframe-latest", mode, false)
 }
 
 // DownloadConfluence 下载confluence文档
@@ -91,88 +95,306 @@ func DownloadGoFrameLatest(mode string) {
 // createTime: 2023-07-27 15:26:56
 //
 // author: hailaz
-func DownloadConfluence(mainURL string, outputDir string, mode string, withComments bool) {
-	if withComments {
-		outputDir = outputDir + "-with-comments"
-	}
-	doc := NewDocDownload(mainURL, outputDir)
-	doc.PageToMD = PageToMD
-	doc.Mode = mode
+func DownloadConfluence(ctx context.Context, mainURL string, outputDir string, mode string, withComments bool, opts ...DocOption) (*RunSummary, error) {
+	return DownloadWithAdapter(ctx, &ConfluenceAdapter{WithComments: withComments}, mainURL, outputDir, mode, opts...)
+}
 
-	doc.GetBrowser().DefaultDevice(devices.Device{
-		AcceptLanguage: "zh-CN",
+func init() {
+	RegisterAdapter("confluence", func() SiteAdapter {
+		return &ConfluenceAdapter{}
 	})
+}
+
+// ConfluenceAdapter confluence适配器
+type ConfluenceAdapter struct {
+	BaseAdapter
+	WithComments bool   // 是否保留评论
+	API          bool   // 通过REST API获取页面层级，不再点击页面树
+	APIBody      bool   // markdown模式下通过REST API获取body.export_view，不打开页面
+	Space        string // 导出整个空间，每个根页面(包括孤立页面)为一个顶层书签，通过REST API获取
+	SpaceSplit   bool   // 导出空间时每个根页面另外保存为单独的pdf
+
+	clientOnce sync.Once
+	client     *ConfluenceClient
+}
+
+// Name description
+func (a *ConfluenceAdapter) Name() string {
+	return "confluence"
+}
+
+// Brief description
+func (a *ConfluenceAdapter) Brief() string {
+	return "confluence文档转换为pdf"
+}
 
+// Options description
+func (a *ConfluenceAdapter) Options() []AdapterOption {
+	return []AdapterOption{
+		{Name: "comments", Brief: "保留评论", Orphan: true},
+		{Name: "api", Brief: "通过REST API获取页面层级，浏览器只用于渲染pdf", Orphan: true},
+		{Name: "api-body", Brief: "markdown模式下通过REST API获取正文(body.export_view)", Orphan: true},
+		{Name: "space", Brief: "导出整个空间(空间key)，包括不在首页下的孤立页面，index为confluence上任一页面地址"},
+		{Name: "space-split", Brief: "导出空间时每个根页面另外保存为单独的pdf", Orphan: true},
+	}
+}
+
+// SetOption description
+func (a *ConfluenceAdapter) SetOption(name string, value string) (err error) {
+	switch name {
+	case "comments":
+		a.WithComments, err = ParseBoolOption(name, value)
+		return err
+	case "api":
+		a.API, err = ParseBoolOption(name, value)
+		return err
+	case "api-body":
+		a.APIBody, err = ParseBoolOption(name, value)
+		return err
+	case "space":
+		a.Space = value
+		return nil
+	case "space-split":
+		a.SpaceSplit, err = ParseBoolOption(name, value)
+		return err
+	}
+	return a.BaseAdapter.SetOption(name, value)
+}
+
+// Init description
+//
+// createTime: 2026-10-18 09:05:00
+func (a *ConfluenceAdapter) Init(doc *DocDownload) error {
+	if a.WithComments {
+		doc.outputDir = doc.outputDir + "-with-comments"
+	}
+	doc.AcceptLanguage = "zh-CN"
 	doc.OpDelay = 100 * time.Millisecond
+	doc.MergePDFNums = 100
+	// 空间的根页面已包含首页
+	doc.IsDownloadMain = a.Space == ""
+	return nil
+}
 
-	doc.SavePDFBefore = func(page *rod.Page) {
-		// 保存pdf前可自定义操作
-		page.MustEval(`() => {
-			// 右侧菜单加长显示
-			var tocMacroDiv = document.querySelector("div.toc-macro");
-			if(tocMacroDiv&&tocMacroDiv.style){
-				tocMacroDiv.style.maxHeight = "5000px";
-			} 
-
-
-			// 代码块自动换行
-			
-			// 获取所有的 <pre> 元素
-			const preElements = document.querySelectorAll('pre');
-
-			// 循环遍历每个元素并设置样式
-			preElements.forEach((preElement) => {
-			preElement.style.whiteSpace = 'pre-wrap';
-			preElement.style.wordWrap = 'break-word';
-			});
-
-			// 移除页脚
-			var element = document.getElementById("footer");
-			if (element) {
-				element.parentNode.removeChild(element);
-			}
-			
-		}`)
-		// 移除评论
-		if !withComments {
-			page.MustEval(`() => {
-				var elementToRemove = document.getElementById('comments-section');
-				// 确认元素存在后再删除
-				if (elementToRemove) {
-					// 获取父级元素，并从父级中移除要删除的元素
-					var parentElement = elementToRemove.parentNode;
-					parentElement.removeChild(elementToRemove);
-				}
-			}`)
+// useAPI 导出空间时只能通过REST API获取页面
+func (a *ConfluenceAdapter) useAPI() bool {
+	return a.API || a.Space != ""
+}
+
+// MenuRootSelector 使用REST API时不需要打开菜单页
+func (a *ConfluenceAdapter) MenuRootSelector() string {
+	if a.useAPI() {
+		return ""
+	}
+	return "ul.plugin_pagetree_children_list.plugin_pagetree_children_list_noleftspace ul"
+}
+
+// ParseMenu description
+func (a *ConfluenceAdapter) ParseMenu(doc *DocDownload, root *rod.Element, level int, dirPath string) ([]*DocNode, error) {
+	if a.Space != "" {
+		return a.parseSpaceMenu(doc)
+	}
+	if a.API {
+		return a.parseAPIMenu(doc)
+	}
+	return ParseConfluenceMenu(doc, root, level, dirPath), nil
+}
+
+// apiClient REST API客户端，请求超时使用PageTimeout
+//
+// createTime: 2026-10-18 09:39:30
+func (a *ConfluenceAdapter) apiClient(doc *DocDownload) *ConfluenceClient {
+	a.clientOnce.Do(func() {
+		base, err := ConfluenceBaseURL(doc.MainURL)
+		if err != nil {
+			base = doc.baseURL
 		}
+		a.client = NewConfluenceClient(base, doc.HTTPClient())
+		a.client.Timeout = doc.PageTimeout
+	})
+	return a.client
+}
 
+// parseAPIMenu 通过REST API获取入口页的全部子页面，markdown模式下生成front matter
+//
+// createTime: 2026-10-18 09:39:30
+func (a *ConfluenceAdapter) parseAPIMenu(doc *DocDownload) ([]*DocNode, error) {
+	client := a.apiClient(doc)
+	id, err := client.PageID(doc.Context(), doc.MainURL)
+	if err != nil {
+		return nil, fmt.Errorf("获取入口页id失败: %w", err)
 	}
-	doc.MergePDFNums = 100
-	doc.PageToPDF = func(page *rod.Page, filePath string) error {
-		var width float64 = 15
-		req := &proto.PagePrintToPDF{
-			PrintBackground: true,
-			PaperWidth:      &width,
+	log.Println("通过REST API获取页面层级", client.BaseURL, id)
+	nodes, err := client.PageTree(doc.Context(), id)
+	if err != nil {
+		return nil, fmt.Errorf("获取页面层级失败: %w", err)
+	}
+	if doc.Mode == DocDownloadModeMD {
+		setConfluenceFrontMatter(nodes)
+		SaveNodesMap(nodes, doc.OutputDir(), doc.FileExt())
+	}
+	return nodes, nil
+}
+
+// parseSpaceMenu 通过REST API获取空间的全部根页面及其子页面
+//
+// createTime: 2026-10-18 09:56:08
+func (a *ConfluenceAdapter) parseSpaceMenu(doc *DocDownload) ([]*DocNode, error) {
+	client := a.apiClient(doc)
+	log.Println("通过REST API获取空间页面", client.BaseURL, a.Space)
+	nodes, err := client.SpaceTree(doc.Context(), a.Space)
+	if err != nil {
+		return nil, fmt.Errorf("获取空间页面失败: %w", err)
+	}
+	log.Printf("空间%s共%d个根页面", a.Space, len(nodes))
+	if doc.Mode == DocDownloadModeMD {
+		setConfluenceFrontMatter(nodes)
+		SaveNodesMap(nodes, doc.OutputDir(), doc.FileExt())
+	}
+	return nodes, nil
+}
+
+// setConfluenceFrontMatter 与页面树解析一致，front matter包含标题和同级中的顺序
+func setConfluenceFrontMatter(nodes []*DocNode) {
+	for index, node := range nodes {
+		quotation := "'"
+		if strings.Contains(node.Title, "'") {
+			quotation = "\""
 		}
+		node.FrontMatter = fmt.Sprintf("---\ntitle: %s%s%s\nsidebar_position: %d\n---\n\n", quotation, node.Title, quotation, index)
+		setConfluenceFrontMatter(node.Children)
+	}
+}
 
-		err := PageToPDFWithCfg(page, filePath, req)
+// PageToMD 开启api-body时从REST API获取正文，否则打开页面提取
+//
+// createTime: 2026-10-18 09:05:00
+func (a *ConfluenceAdapter) PageToMD(doc *DocDownload, filePath string, pageURL string) error {
+	if !a.APIBody {
+		return PageToMD(doc, filePath, pageURL)
+	}
+	log.Println("PageToMD(api)", filePath)
+	return CachedMD(doc, filePath, pageURL, func() (string, error) {
+		client := a.apiClient(doc)
+		id, err := client.PageID(doc.Context(), pageURL)
 		if err != nil {
-			return err
+			return "", err
 		}
-		// 获取页数，合并成单页
-		pageCount, err := api.PageCountFile(filePath)
-		if err == nil {
-			height := 11 * float64(pageCount)
-			req.PaperHeight = &height
-			return PageToPDFWithCfg(page, filePath, req)
+		body, err := client.ExportView(doc.Context(), id)
+		if err != nil {
+			return "", NewPageError(StagePage, pageURL, filePath, err)
 		}
+		opt := &MarkdownOptions{ContentSelector: "#export-view"}
+		return ContentHTML(doc, `<div id="export-view">`+body+`</div>`, pageURL, opt, doc.FetchBytes)
+	})
+}
 
-		return nil
+// PrintOptions description
+func (a *ConfluenceAdapter) PrintOptions() *PrintOptions {
+	return &PrintOptions{PaperWidth: 15, PageHeight: 11, SinglePage: true}
+}
+
+// MarkdownOptions description
+func (a *ConfluenceAdapter) MarkdownOptions() *MarkdownOptions {
+	return &MarkdownOptions{
+		ContentSelector: "#main-content",
+		Strip: []string{
+			"div.page-metadata",
+			"div.cell.aside",
+			"#likes-and-labels-container",
+			"#comments-section",
+		},
+	}
+}
+
+// Fingerprint 使用confluence页面版本号作为指纹，获取不到时使用正文的hash
+//
+// createTime: 2026-10-18 09:18:43
+func (a *ConfluenceAdapter) Fingerprint(doc *DocDownload, pageURL string) (string, error) {
+	if a.useAPI() || a.APIBody {
+		client := a.apiClient(doc)
+		id, err := client.PageID(doc.Context(), pageURL)
+		if err != nil {
+			return "", err
+		}
+		page, err := client.Page(doc.Context(), id, "version")
+		if err != nil {
+			return "", err
+		}
+		return "version:" + strconv.Itoa(page.Version.Number), nil
+	}
+	page, err := doc.OpenPage(pageURL)
+	if err != nil {
+		return "", err
+	}
+	defer doc.ClosePage(page)
+	if has, meta, err := page.Has(`meta[name="ajs-page-version"]`); err == nil && has {
+		if version, err := meta.Attribute("content"); err == nil && version != nil && *version != "" {
+			return "version:" + *version, nil
+		}
+	}
+	return textFingerprint(page, a.MarkdownOptions().ContentSelector)
+}
+
+// PreparePage 保存pdf前可自定义操作
+//
+// createTime: 2026-10-18 09:05:00
+func (a *ConfluenceAdapter) PreparePage(doc *DocDownload, page *rod.Page) error {
+	_, err := page.Eval(`() => {
+		// 右侧菜单加长显示
+		var tocMacroDiv = document.querySelector("div.toc-macro");
+		if(tocMacroDiv&&tocMacroDiv.style){
+			tocMacroDiv.style.maxHeight = "5000px";
+		} 
+
+
+		// 代码块自动换行
+		
+		// 获取所有的 <pre> 元素
+		const preElements = document.querySelectorAll('pre');
+
+		// 循环遍历每个元素并设置样式
+		preElements.forEach((preElement) => {
+		preElement.style.whiteSpace = 'pre-wrap';
+		preElement.style.wordWrap = 'break-word';
+		});
+
+		// 移除页脚
+		var element = document.getElementById("footer");
+		if (element) {
+			element.parentNode.removeChild(element);
+		}
+		
+	}`)
+	if err != nil {
+		return err
+	}
+	// 移除评论
+	if !a.WithComments {
+		_, err = page.Eval(`() => {
+			var elementToRemove = document.getElementById('comments-section');
+			// 确认元素存在后再删除
+			if (elementToRemove) {
+				// 获取父级元素，并从父级中移除要删除的元素
+				var parentElement = elementToRemove.parentNode;
+				parentElement.removeChild(elementToRemove);
+			}
+		}`)
+	}
+	return err
+}
+
+// Finish 复制pdf到其它目录，markdown模式下替换文档链接
+//
+// createTime: 2026-10-18 09:05:00
+func (a *ConfluenceAdapter) Finish(doc *DocDownload) error {
+	if doc.Mode == DocDownloadModePDF && a.Space != "" && a.SpaceSplit {
+		files, err := splitTopBookmarks(doc)
+		if err != nil {
+			return err
+		}
+		doc.SplitFiles = append(doc.SplitFiles, files...)
 	}
-	doc.MenuRootSelector = "ul.plugin_pagetree_children_list.plugin_pagetree_children_list_noleftspace ul"
-	doc.ParseMenu = ParseConfluenceMenu
-	doc.IsDownloadMain = true
-	doc.Start()
 	if doc.Mode == DocDownloadModePDF {
 		// 复制文件到其它目录
 		log.Println(doc.Move("./dist"))
@@ -182,7 +404,7 @@ func DownloadConfluence(mainURL string, outputDir string, mode string, withComme
 		// 地址转换
 		files, err := gfile.ScanDir(doc.OutputDir(), "*.md", true)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		regx := regexp.MustCompile(`\(/pages/viewpage.action\?pageId=\d+.*?\)|\(/display/.*?\)|\(/download/attachments/.*?\)`)
 		for _, file := range files {
@@ -231,10 +453,61 @@ func DownloadConfluence(mainURL string, outputDir string, mode string, withComme
 
 			err := gfile.PutContents(file, contents)
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
 		}
 	}
+	return nil
+}
+
+// splitTopBookmarks 按顶层书签把合并后的pdf另存为多个文件，文件名为 输出目录-序号-标题.pdf，
+// 每个文件保留该书签下的子书签
+//
+// createTime: 2026-10-18 09:56:08
+func splitTopBookmarks(doc *DocDownload) ([]string, error) {
+	pdfName := doc.OutputPDF()
+	if !gfile.Exists(pdfName) || len(doc.bookmark) == 0 {
+		return nil, nil
+	}
+	pageCount, err := api.PageCountFile(pdfName)
+	if err != nil {
+		return nil, NewPageError(StageSplit, "", pdfName, err)
+	}
+	files := make([]string, 0, len(doc.bookmark))
+	for i, bm := range doc.bookmark {
+		from, thru := bm.PageFrom, pageCount
+		if i+1 < len(doc.bookmark) {
+			thru = doc.bookmark[i+1].PageFrom - 1
+		}
+		if thru < from {
+			// 根页面及其子页面都渲染失败
+			continue
+		}
+		outFile := fmt.Sprintf("%s-%d-%s.pdf", doc.OutputDir(), i, validFileName.ReplaceAllString(bm.Title, ""))
+		tempFile := outFile + doc.TempSuffix
+		log.Printf("保存根页面%s(%d-%d) %s", bm.Title, from, thru, outFile)
+		if err := api.TrimFile(pdfName, tempFile, []string{fmt.Sprintf("%d-%d", from, thru)}, nil); err != nil {
+			return files, NewPageError(StageSplit, "", outFile, err)
+		}
+		bm.PageThru = 0
+		bms := shiftBookmarks([]pdfcpu.Bookmark{bm}, 1-from)
+		// 末尾渲染失败的子页面指向最后一页
+		var clamp func(bms []pdfcpu.Bookmark)
+		clamp = func(bms []pdfcpu.Bookmark) {
+			for j := range bms {
+				bms[j].PageFrom = min(bms[j].PageFrom, thru-from+1)
+				clamp(bms[j].Kids)
+			}
+		}
+		clamp(bms)
+		err := api.AddBookmarksFile(tempFile, outFile, bms, true, nil)
+		os.Remove(tempFile)
+		if err != nil {
+			return files, NewPageError(StageBookmark, "", outFile, err)
+		}
+		files = append(files, outFile)
+	}
+	return files, nil
 }
 
 // ParseConfluenceMenu 解析菜单
@@ -242,10 +515,14 @@ func DownloadConfluence(mainURL string, outputDir string, mode string, withComme
 // createTime: 2023-07-11 16:13:27
 //
 // author: hailaz
-func ParseConfluenceMenu(doc *DocDownload, root *rod.Element, level int, dirPath string, bms *[]pdfcpu.Bookmark) {
+func ParseConfluenceMenu(doc *DocDownload, root *rod.Element, level int, dirPath string) []*DocNode {
+	nodes := make([]*DocNode, 0)
 	index := 0
 	// 循环当前节点的li
 	for li, err := root.Element("li"); err == nil; li, err = li.Next() {
+		if doc.Context().Err() != nil {
+			return nodes
+		}
 		// 获取当前节点的a标签
 		a, err := li.Element("div.plugin_pagetree_children_content a")
 		if err != nil {
@@ -269,36 +546,17 @@ func ParseConfluenceMenu(doc *DocDownload, root *rod.Element, level int, dirPath
 		docTitle = validFileName.ReplaceAllString(docTitle, "")
 		log.Printf("title: %s\n", docTitle)
 
-		// 人为退出测试
-		// if doc.pageFrom >= 30 {
-		// 	break
-		// }
 		// 拼接完整的url
 		pageURL := doc.baseURL + *href
+		node := &DocNode{
+			Title: docTitle,
+			URL:   pageURL,
+		}
+		nodes = append(nodes, node)
 		if doc.Mode == DocDownloadModePDF {
-			// log.Printf("pageFrom: %d", doc.pageFrom)
-			// 保存书签
-			*bms = append(*bms, pdfcpu.Bookmark{
-				Title:    docTitle,
-				PageFrom: doc.pageFrom,
-			})
 			// 保存pdf
 			fileName := fmt.Sprintf("%d-%s.pdf", index, docTitle)
-			filePath := path.Join(dirPath, fileName)
-			doc.fileList = append(doc.fileList, filePath)
-			err := doc.SavePDF(filePath, pageURL)
-			if err != nil {
-				log.Printf("[err]SavePDF: %s", err)
-				continue
-			}
-			page, err := api.PageCountFile(filePath)
-			if err != nil {
-				log.Printf("[err]PageCountFile: %s", err)
-				continue
-			}
-			doc.pageFrom = doc.pageFrom + page
-
-			log.Printf("文档累计页数%d，当前文件页数%d： %s\n", doc.pageFrom, page, path.Join(dirPath, fileName))
+			node.Path = path.Join(dirPath, fileName)
 		}
 		fileNameMD := ""
 		if a, err := li.Element("div.plugin_pagetree_childtoggle_container a"); err == nil {
@@ -314,26 +572,13 @@ func ParseConfluenceMenu(doc *DocDownload, root *rod.Element, level int, dirPath
 						// log.Printf("[子菜单]: %s", ul.MustText())
 						// 递归子节点
 						dirName := fmt.Sprintf("%d-%s", index, docTitle)
-						if bms != nil {
-							bmsIndex := index
-							// 因为有首页，所以要加1
-							if level == 0 && doc.IsDownloadMain {
-								bmsIndex += 1
-							}
-							// log.Println("bmsIndex", level, bmsIndex, len(*bms))
-							thisBms := &((*bms)[bmsIndex])
-							thisBms.Kids = make([]pdfcpu.Bookmark, 0)
-							doc.ParseMenu(doc, ul, level+1, path.Join(dirPath, dirName), &thisBms.Kids)
-						} else {
-							doc.ParseMenu(doc, ul, level+1, path.Join(dirPath, dirName), nil)
-						}
-
+						node.Children = ParseConfluenceMenu(doc, ul, level+1, path.Join(dirPath, dirName))
 						break
 					} else {
 						log.Printf("尝试第%d次，没有子节点，待重试: %s\n", count, err)
 					}
 
-					if count >= 50 {
+					if count >= 50 || doc.Context().Err() != nil {
 						log.Printf("经过%d次，真的没有子节点\n", count)
 						break
 					}
@@ -346,29 +591,23 @@ func ParseConfluenceMenu(doc *DocDownload, root *rod.Element, level int, dirPath
 			fileNameMD = fmt.Sprintf("%d-%s.md", index, docTitle)
 		}
 		if doc.Mode == DocDownloadModeMD {
-			filePath := ReplacePath(path.Join(dirPath, fileNameMD), doc.OutputDir())
-			doc.SaveMD(filePath, pageURL)
+			node.Path = ReplacePath(path.Join(dirPath, fileNameMD), doc.OutputDir())
 
 			// 这里必须这样转，否则层级出问题
 			SaveMap(ReplacePath(path.Join(dirPath, fmt.Sprintf("%d-%s", index, docTitle)), doc.OutputDir()), pageURL)
 			// 加标题
-			contents := gfile.GetContents(filePath)
 			// 引号
 			quotation := "'"
 			if strings.Contains(srcTitle, "'") {
 				quotation = "\""
 			}
-			mdTitle := fmt.Sprintf("---\ntitle: %s%s%s\nsidebar_position: %d\n---\n\n", quotation, srcTitle, quotation, index)
-			if !strings.HasPrefix(contents, "---") {
-				contents = mdTitle + contents
-				gfile.PutContents(filePath, contents)
-			}
+			node.FrontMatter = fmt.Sprintf("---\ntitle: %s%s%s\nsidebar_position: %d\n---\n\n", quotation, srcTitle, quotation, index)
 		}
 		// mapData := fmt.Sprintf("%s=>%s\n", pageURL, ReplacePath(path.Join(dirPath, fileNameMD), doc.OutputDir()))
 		// gfile.PutContentsAppend(path.Join(doc.OutputDir()+"-map", "map.txt"), mapData)
 		index++
 	}
-
+	return nodes
 }
 
 // SaveMap description
@@ -396,6 +635,33 @@ func SaveMap(filePath string, pageURL string) {
 	mapData[pageURL] = filePath
 }
 
+// SaveNodesMap 与页面树解析一样生成REST API获取的页面的输出路径，有子页面的页面
+// 放在同名目录中，并用 SaveMap 记录页面地址对应的文档路径，用于替换文档中的站内链接
+//
+// createTime: 2026-10-18 09:39:30
+func SaveNodesMap(nodes []*DocNode, dirPath string, ext string) {
+	for index, node := range nodes {
+		name := fmt.Sprintf("%d-%s", index, validFileName.ReplaceAllString(node.Title, ""))
+		fileName := name + ext
+		if len(node.Children) > 0 {
+			fileName = path.Join(name, name+ext)
+		}
+		if node.URL != "" {
+			node.Path = ReplacePath(path.Join(dirPath, fileName), dirPath)
+			SaveMap(ReplacePath(path.Join(dirPath, name), dirPath), node.URL)
+		}
+		SaveNodesMap(node.Children, path.Join(dirPath, name), ext)
+	}
+}
+
+// MapPath 返回 SaveMap 记录的页面地址对应的文档路径
+//
+// createTime: 2026-10-18 09:39:30
+func MapPath(pageURL string) (string, bool) {
+	filePath, ok := mapData[pageURL]
+	return filePath, ok
+}
+
 // ReplacePath description
 //
 // createTime: 2024-01-31 18:48:10
@@ -406,123 +672,3 @@ func ReplacePath(filePath string, outPath string) string {
 	filePath = strings.ReplaceAll(filePath, ")", "")
 	return filePath
 }
-
-// PageToMD description
-//
-// createTime: 2023-07-28 16:45:39
-//
-// author: hailaz
-func PageToMD(doc *DocDownload, filePath string, pageUrl string) error {
-	log.Println("PageToMD", filePath)
-
-	cacheHtml := strings.ReplaceAll(filePath, doc.OutputDir(), doc.HTMLDir())
-	cacheHtml = strings.TrimSuffix(cacheHtml, ".md") + ".html"
-	html := ""
-
-	if _, err := os.Stat(cacheHtml); os.IsNotExist(err) {
-		// 加个缓存，免得每次都下载
-		page := doc.browser.MustPage(pageUrl).MustWaitStable()
-		defer page.Close()
-		html, _ = page.HTML()
-		queryDoc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
-		if err != nil {
-			log.Fatal(err)
-		}
-		queryDoc.Find("div.page-metadata").Remove()
-		queryDoc.Find("div.cell.aside").Remove()
-		queryDoc.Find("#likes-and-labels-container").Remove()
-		queryDoc.Find("#comments-section").Remove()
-		// page.MustElement("img").MustResource()
-		host := doc.baseURL
-		// pageDir := path.Dir(filePath)
-		pageDir := path.Join(doc.StaticDir())
-
-		queryDoc.Find("#main-content").Find("img").Each(func(i int, s *goquery.Selection) {
-			src, _ := s.Attr("src")
-			// log.Println("img src:", src)
-			if strings.HasPrefix(src, "https://") || strings.HasPrefix(src, "http://") {
-				return
-			}
-			// resBaseName := strings.Split(filepath.Base(src), "?")[0]
-			// 保存资源文件
-			res, err := page.GetResource(host + src)
-			if err != nil {
-				log.Fatal(err)
-				s.SetAttr("src", host+src)
-			}
-			// 使用新的文件名，避免无法识别
-			resBaseName := strings.Split(src, "?")[0]
-			resExt := filepath.Ext(resBaseName)
-			resMD5Name, _ := gmd5.EncryptString(resBaseName)
-			srcPath := path.Join("/markdown", resMD5Name+resExt)
-			resPath := path.Join(pageDir, srcPath)
-
-			// fmt.Println("resPath", resPath)
-			// log.Println("save file:", resPath)
-			err = gfile.PutBytes(resPath, res)
-			if err != nil {
-				log.Fatal(err)
-			}
-			// 替换src
-			s.SetAttr("src", srcPath)
-			// s.SetAttr("src", host+src)
-			// log.Println("src change", resBaseName)
-		})
-		html, _ = queryDoc.Find("#main-content").Html()
-
-		gfile.PutContents(cacheHtml, html)
-	} else {
-		html = gfile.GetContents(cacheHtml)
-	}
-
-	converter := md.NewConverter("", true, nil)
-	// md文档只能有一个一级标题，所以需要自动降级
-	converter.AddRules(md.Rule{
-		Filter: []string{"h1", "h2", "h3", "h4", "h5", "h6"},
-		Replacement: func(content string, selec *goquery.Selection, opt *md.Options) *string {
-			if strings.TrimSpace(content) == "" {
-				return nil
-			}
-
-			content = strings.Replace(content, "\n", " ", -1)
-			content = strings.Replace(content, "\r", " ", -1)
-			content = strings.Replace(content, `#`, `\#`, -1)
-			content = strings.TrimSpace(content)
-
-			insideLink := selec.ParentsFiltered("a").Length() > 0
-			if insideLink {
-				text := opt.StrongDelimiter + content + opt.StrongDelimiter
-				text = md.AddSpaceIfNessesary(selec, text)
-				return &text
-			}
-
-			node := goquery.NodeName(selec)
-			level, err := strconv.Atoi(node[1:])
-			if err != nil {
-				return nil
-			}
-
-			if opt.HeadingStyle == "setext" && level < 3 {
-				line := "-"
-				if level == 1 {
-					line = "="
-				}
-
-				underline := strings.Repeat(line, len(content))
-				return md.String("\n\n" + content + "\n" + underline + "\n\n")
-			}
-
-			prefix := strings.Repeat("#", level+1)
-			text := "\n\n" + prefix + " " + content + "\n\n"
-			return &text
-		},
-	})
-	converter.Use(plugin.Strikethrough(""))
-	converter.Use(ConverterTable())
-	markdown, err := converter.ConvertString(html)
-	if err != nil {
-		log.Fatal(err)
-	}
-	gfile.PutContents(filePath, markdown)
-	return nil
-}
@@ -0,0 +1,326 @@
+package doc2pdf
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// confluenceCloudPage cloud版的页面地址 /spaces/KEY/pages/ID/Title
+	confluenceCloudPage = regexp.MustCompile(`^/spaces/[^/]+/pages/(\d+)`)
+	// confluenceDisplayPage 按空间和标题访问的页面地址 /display/KEY/Title
+	confluenceDisplayPage = regexp.MustCompile(`^/display/([^/]+)(?:/([^/]+))?/?$`)
+)
+
+// ConfluenceClient confluence REST API客户端，用于不经过浏览器获取页面层级和正文
+type ConfluenceClient struct {
+	BaseURL  string        // confluence地址，包含上下文路径，如 https://example.com/wiki
+	Client   *http.Client  // http客户端
+	Timeout  time.Duration // 单个请求的超时时间，0为不限制
+	PageSize int           // 分页大小
+}
+
+// ConfluencePage REST API返回的页面
+type ConfluencePage struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Status  string `json:"status"`
+	Title   string `json:"title"`
+	Version struct {
+		Number int    `json:"number"`
+		When   string `json:"when"`
+	} `json:"version"`
+	Metadata struct {
+		Labels struct {
+			Results []struct {
+				Name string `json:"name"`
+			} `json:"results"`
+		} `json:"labels"`
+	} `json:"metadata"`
+	Body struct {
+		ExportView struct {
+			Value string `json:"value"`
+		} `json:"export_view"`
+	} `json:"body"`
+	Links struct {
+		WebUI string `json:"webui"`
+	} `json:"_links"`
+}
+
+// confluencePageExpand 页面树中需要展开的字段，用于记录标签和最后修改时间
+const confluencePageExpand = "version,metadata.labels"
+
+// Meta 页面元数据，labels为逗号分隔的标签，lastModified为最后修改时间
+//
+// createTime: 2026-10-18 09:56:08
+func (p *ConfluencePage) Meta() map[string]string {
+	meta := make(map[string]string)
+	labels := make([]string, 0, len(p.Metadata.Labels.Results))
+	for _, label := range p.Metadata.Labels.Results {
+		labels = append(labels, label.Name)
+	}
+	if len(labels) > 0 {
+		meta["labels"] = strings.Join(labels, ",")
+	}
+	if p.Version.When != "" {
+		meta["lastModified"] = p.Version.When
+	}
+	if len(meta) == 0 {
+		return nil
+	}
+	return meta
+}
+
+// confluenceResults 分页结果
+type confluenceResults struct {
+	Results []*ConfluencePage `json:"results"`
+	Start   int               `json:"start"`
+	Limit   int               `json:"limit"`
+	Size    int               `json:"size"`
+	Links   struct {
+		Next string `json:"next"`
+	} `json:"_links"`
+}
+
+// NewConfluenceClient 创建客户端，client为nil时使用http.DefaultClient
+//
+// createTime: 2026-10-18 09:39:30
+func NewConfluenceClient(baseURL string, client *http.Client) *ConfluenceClient {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &ConfluenceClient{
+		BaseURL:  strings.TrimSuffix(baseURL, "/"),
+		Client:   client,
+		PageSize: 50,
+	}
+}
+
+// ConfluenceBaseURL 从页面地址中获取confluence地址，保留/display、/pages、/spaces之前的上下文路径
+//
+// createTime: 2026-10-18 09:39:30
+func ConfluenceBaseURL(pageURL string) (string, error) {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return "", err
+	}
+	contextPath := ""
+	for _, marker := range []string{"/display/", "/pages/", "/spaces/"} {
+		if i := strings.Index(u.Path, marker); i >= 0 {
+			contextPath = u.Path[:i]
+			break
+		}
+	}
+	return fmt.Sprintf("%s://%s%s", u.Scheme, u.Host, contextPath), nil
+}
+
+// get 请求API并解码json
+//
+// createTime: 2026-10-18 09:39:30
+func (c *ConfluenceClient) get(ctx context.Context, apiPath string, query url.Values, v any) error {
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+	apiURL := c.BaseURL + apiPath
+	if len(query) > 0 {
+		apiURL += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("请求%s失败: %s", apiPath, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// list 读取分页接口的全部结果
+//
+// createTime: 2026-10-18 09:39:30
+func (c *ConfluenceClient) list(ctx context.Context, apiPath string, query url.Values) ([]*ConfluencePage, error) {
+	pageSize := c.PageSize
+	if pageSize <= 0 {
+		pageSize = 50
+	}
+	if query == nil {
+		query = url.Values{}
+	}
+	pages := make([]*ConfluencePage, 0)
+	for start := 0; ; {
+		query.Set("start", strconv.Itoa(start))
+		query.Set("limit", strconv.Itoa(pageSize))
+		var res confluenceResults
+		if err := c.get(ctx, apiPath, query, &res); err != nil {
+			return nil, err
+		}
+		pages = append(pages, res.Results...)
+		if res.Links.Next == "" || len(res.Results) == 0 {
+			return pages, nil
+		}
+		start += len(res.Results)
+	}
+}
+
+// Page 获取页面，expand为需要展开的字段，如 version,body.export_view
+//
+// createTime: 2026-10-18 09:39:30
+func (c *ConfluenceClient) Page(ctx context.Context, id string, expand string) (*ConfluencePage, error) {
+	query := url.Values{}
+	if expand != "" {
+		query.Set("expand", expand)
+	}
+	page := &ConfluencePage{}
+	if err := c.get(ctx, "/rest/api/content/"+url.PathEscape(id), query, page); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// ChildPages 获取子页面，按页面树中的顺序返回
+//
+// createTime: 2026-10-18 09:39:30
+func (c *ConfluenceClient) ChildPages(ctx context.Context, id string) ([]*ConfluencePage, error) {
+	return c.list(ctx, "/rest/api/content/"+url.PathEscape(id)+"/child/page", url.Values{"expand": {confluencePageExpand}})
+}
+
+// SpaceRootPages 获取空间中没有父页面的全部页面，包括首页和不在首页下的孤立页面
+//
+// createTime: 2026-10-18 09:56:08
+func (c *ConfluenceClient) SpaceRootPages(ctx context.Context, spaceKey string) ([]*ConfluencePage, error) {
+	query := url.Values{"depth": {"root"}, "expand": {confluencePageExpand}}
+	return c.list(ctx, "/rest/api/space/"+url.PathEscape(spaceKey)+"/content/page", query)
+}
+
+// SpaceTree 获取空间的全部页面，每个根页面为一个顶层节点
+//
+// createTime: 2026-10-18 09:56:08
+func (c *ConfluenceClient) SpaceTree(ctx context.Context, spaceKey string) ([]*DocNode, error) {
+	roots, err := c.SpaceRootPages(ctx, spaceKey)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]*DocNode, 0, len(roots))
+	for _, root := range roots {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		node := &DocNode{Title: root.Title, URL: c.PageURL(root.ID), Meta: root.Meta()}
+		if node.Children, err = c.PageTree(ctx, root.ID); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// ExportView 获取页面导出格式的正文html
+//
+// createTime: 2026-10-18 09:39:30
+func (c *ConfluenceClient) ExportView(ctx context.Context, id string) (string, error) {
+	page, err := c.Page(ctx, id, "body.export_view")
+	if err != nil {
+		return "", err
+	}
+	return page.Body.ExportView.Value, nil
+}
+
+// PageTree 递归获取子页面生成文档节点，节点地址为 PageURL(id)，任一子页面获取失败时返回错误
+//
+// createTime: 2026-10-18 09:39:30
+func (c *ConfluenceClient) PageTree(ctx context.Context, id string) ([]*DocNode, error) {
+	children, err := c.ChildPages(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]*DocNode, 0, len(children))
+	for _, child := range children {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		node := &DocNode{Title: child.Title, URL: c.PageURL(child.ID), Meta: child.Meta()}
+		if node.Children, err = c.PageTree(ctx, child.ID); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// PageURL 页面的浏览地址
+//
+// createTime: 2026-10-18 09:39:30
+func (c *ConfluenceClient) PageURL(id string) string {
+	return c.BaseURL + "/pages/viewpage.action?pageId=" + url.QueryEscape(id)
+}
+
+// PageID 从页面地址中获取页面id，支持pageId参数、cloud版地址、/display/KEY/Title和空间首页/display/KEY
+//
+// createTime: 2026-10-18 09:39:30
+func (c *ConfluenceClient) PageID(ctx context.Context, pageURL string) (string, error) {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return "", err
+	}
+	if id := u.Query().Get("pageId"); id != "" {
+		return id, nil
+	}
+	base, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return "", err
+	}
+	rel := strings.TrimPrefix(u.EscapedPath(), base.EscapedPath())
+	if m := confluenceCloudPage.FindStringSubmatch(rel); m != nil {
+		return m[1], nil
+	}
+	m := confluenceDisplayPage.FindStringSubmatch(rel)
+	if m == nil {
+		return "", fmt.Errorf("无法从地址获取页面id: %s", pageURL)
+	}
+	spaceKey, err := url.PathUnescape(m[1])
+	if err != nil {
+		return "", err
+	}
+	if m[2] == "" {
+		// 空间首页
+		var space struct {
+			Homepage ConfluencePage `json:"homepage"`
+		}
+		if err := c.get(ctx, "/rest/api/space/"+url.PathEscape(spaceKey), url.Values{"expand": {"homepage"}}, &space); err != nil {
+			return "", err
+		}
+		if space.Homepage.ID == "" {
+			return "", fmt.Errorf("空间没有首页: %s", spaceKey)
+		}
+		return space.Homepage.ID, nil
+	}
+	// 标题中的空格编码为+
+	title, err := url.QueryUnescape(m[2])
+	if err != nil {
+		return "", err
+	}
+	var res confluenceResults
+	query := url.Values{"spaceKey": {spaceKey}, "title": {title}, "type": {"page"}}
+	if err := c.get(ctx, "/rest/api/content", query, &res); err != nil {
+		return "", err
+	}
+	if len(res.Results) == 0 {
+		return "", fmt.Errorf("页面不存在: %s/%s", spaceKey, title)
+	}
+	return res.Results[0].ID, nil
+}
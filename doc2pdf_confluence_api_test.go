@@ -0,0 +1,229 @@
+package doc2pdf_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hailaz/doc2pdf"
+)
+
+// newConfluenceServer 模拟confluence REST API，页面1有3个子页面，分页大小为2，空间gf的根页面为1和孤立页面9
+func newConfluenceServer(t *testing.T) *httptest.Server {
+	children := map[string][]string{
+		"1": {"2", "3", "4"},
+		"2": {"5"},
+	}
+	page := func(id string) map[string]any {
+		return map[string]any{
+			"id":      id,
+			"type":    "page",
+			"title":   "Page " + id,
+			"version": map[string]any{"number": 7, "when": "2026-10-01T08:00:00.000Z"},
+			"metadata": map[string]any{"labels": map[string]any{"results": []any{
+				map[string]any{"name": "guide"},
+				map[string]any{"name": "v" + id},
+			}}},
+			"body": map[string]any{"export_view": map[string]any{"value": "<h1>Page " + id + `</h1><img src="/download/attachments/` + id + `/a.png">`}},
+		}
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/wiki/rest/api/content/", func(w http.ResponseWriter, r *http.Request) {
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/wiki/rest/api/content/"), "/")
+		if len(parts) == 1 {
+			json.NewEncoder(w).Encode(page(parts[0]))
+			return
+		}
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		ids := children[parts[0]]
+		end := min(start+limit, len(ids))
+		results := make([]any, 0)
+		for _, id := range ids[min(start, end):end] {
+			results = append(results, page(id))
+		}
+		links := map[string]any{}
+		if end < len(ids) {
+			links["next"] = fmt.Sprintf("/rest/api/content/%s/child/page?start=%d&limit=%d", parts[0], end, limit)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"results": results, "start": start, "limit": limit, "size": len(results), "_links": links})
+	})
+	mux.HandleFunc("/wiki/rest/api/content", func(w http.ResponseWriter, r *http.Request) {
+		results := make([]any, 0)
+		if r.URL.Query().Get("spaceKey") == "gf" && r.URL.Query().Get("title") == "Quick Start" {
+			results = append(results, page("1"))
+		}
+		json.NewEncoder(w).Encode(map[string]any{"results": results})
+	})
+	mux.HandleFunc("/wiki/rest/api/space/gf", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"key": "gf", "homepage": page("1")})
+	})
+	mux.HandleFunc("/wiki/rest/api/space/gf/content/page", func(w http.ResponseWriter, r *http.Request) {
+		results := make([]any, 0)
+		if r.URL.Query().Get("depth") == "root" {
+			// 首页和孤立页面
+			results = append(results, page("1"), page("9"))
+		}
+		json.NewEncoder(w).Encode(map[string]any{"results": results})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestConfluenceClientPageID description
+//
+// createTime: 2026-10-18 09:39:30
+func TestConfluenceClientPageID(t *testing.T) {
+	server := newConfluenceServer(t)
+	base, err := doc2pdf.ConfluenceBaseURL(server.URL + "/wiki/display/gf/Quick+Start")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if base != server.URL+"/wiki" {
+		t.Fatalf("base = %s", base)
+	}
+	client := doc2pdf.NewConfluenceClient(base, server.Client())
+	for _, pageURL := range []string{
+		"/wiki/pages/viewpage.action?pageId=1",
+		"/wiki/spaces/gf/pages/1/Quick+Start",
+		"/wiki/display/gf/Quick+Start",
+		"/wiki/display/gf",
+	} {
+		id, err := client.PageID(context.Background(), server.URL+pageURL)
+		if err != nil {
+			t.Errorf("PageID(%s): %s", pageURL, err)
+		} else if id != "1" {
+			t.Errorf("PageID(%s) = %s", pageURL, id)
+		}
+	}
+	if _, err := client.PageID(context.Background(), server.URL+"/wiki/display/gf/Missing"); err == nil {
+		t.Error("missing page should fail")
+	}
+}
+
+// TestConfluenceClientPageTree description
+//
+// createTime: 2026-10-18 09:39:30
+func TestConfluenceClientPageTree(t *testing.T) {
+	server := newConfluenceServer(t)
+	client := doc2pdf.NewConfluenceClient(server.URL+"/wiki", server.Client())
+	client.PageSize = 2
+	nodes, err := client.PageTree(context.Background(), "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree := &doc2pdf.DocTree{Nodes: nodes}
+	var got []string
+	tree.Walk(func(node *doc2pdf.DocNode, level int) bool {
+		got = append(got, fmt.Sprintf("%d %s %s", level, node.Title, strings.TrimPrefix(node.URL, server.URL)))
+		return true
+	})
+	want := []string{
+		"0 Page 2 /wiki/pages/viewpage.action?pageId=2",
+		"1 Page 5 /wiki/pages/viewpage.action?pageId=5",
+		"0 Page 3 /wiki/pages/viewpage.action?pageId=3",
+		"0 Page 4 /wiki/pages/viewpage.action?pageId=4",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	body, err := client.ExportView(context.Background(), "5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(body, "<h1>Page 5</h1>") {
+		t.Errorf("unexpected body %s", body)
+	}
+	page, err := client.Page(context.Background(), "5", "version")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Version.Number != 7 {
+		t.Errorf("version = %d", page.Version.Number)
+	}
+}
+
+// TestConfluenceClientSpaceTree description
+//
+// createTime: 2026-10-18 09:56:08
+func TestConfluenceClientSpaceTree(t *testing.T) {
+	server := newConfluenceServer(t)
+	client := doc2pdf.NewConfluenceClient(server.URL+"/wiki", server.Client())
+	nodes, err := client.SpaceTree(context.Background(), "gf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 2 || nodes[0].Title != "Page 1" || nodes[1].Title != "Page 9" {
+		t.Fatalf("unexpected roots %+v", nodes)
+	}
+	if len(nodes[0].Children) != 3 || len(nodes[1].Children) != 0 {
+		t.Errorf("children = %d, %d", len(nodes[0].Children), len(nodes[1].Children))
+	}
+	meta := nodes[0].Children[0].Meta
+	if meta["labels"] != "guide,v2" || meta["lastModified"] != "2026-10-01T08:00:00.000Z" {
+		t.Errorf("meta = %v", meta)
+	}
+
+	tree := &doc2pdf.DocTree{Nodes: nodes}
+	tree.FillPaths("output/space", ".pdf")
+	m := doc2pdf.NewManifest("", doc2pdf.DocDownloadModePDF, tree)
+	if entry := m.FindURL(nodes[1].URL); entry == nil || entry.Meta["labels"] != "guide,v9" {
+		t.Errorf("manifest entry = %+v", entry)
+	}
+}
+
+// TestConfluenceAPIMap REST API获取的页面也记录页面地址对应的文档路径
+//
+// createTime: 2026-10-18 09:39:30
+func TestConfluenceAPIMap(t *testing.T) {
+	server := newConfluenceServer(t)
+	client := doc2pdf.NewConfluenceClient(server.URL+"/wiki", server.Client())
+	nodes, err := client.PageTree(context.Background(), "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc2pdf.SaveNodesMap(nodes, "output/api", ".md")
+	if nodes[0].Path != "output/api/0-Page 2/0-Page 2.md" || nodes[0].Children[0].Path != "output/api/0-Page 2/0-Page 5.md" {
+		t.Errorf("paths = %s, %s", nodes[0].Path, nodes[0].Children[0].Path)
+	}
+	want := map[string]string{
+		nodes[0].URL:             "output/api/Page%202",
+		nodes[0].Children[0].URL: "output/api/Page%202/Page%205",
+		nodes[2].URL:             "output/api/Page%204",
+	}
+	for pageURL, filePath := range want {
+		if got, ok := doc2pdf.MapPath(pageURL); !ok || got != filePath {
+			t.Errorf("MapPath(%s) = %s, want %s", pageURL, got, filePath)
+		}
+	}
+}
+
+// TestConfluenceClientTreeError 任一子页面获取失败时不返回已获取的部分页面
+//
+// createTime: 2026-10-18 09:39:30
+func TestConfluenceClientTreeError(t *testing.T) {
+	server := newConfluenceServer(t)
+	// 页面2、3已获取成功，页面4的子页面请求失败
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/wiki/rest/api/content/4/child") {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		server.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(failing.Close)
+	client := doc2pdf.NewConfluenceClient(failing.URL+"/wiki", failing.Client())
+	if nodes, err := client.PageTree(context.Background(), "1"); err == nil || nodes != nil {
+		t.Errorf("PageTree = %d nodes, %v", len(nodes), err)
+	}
+	if nodes, err := client.SpaceTree(context.Background(), "gf"); err == nil || nodes != nil {
+		t.Errorf("SpaceTree = %d nodes, %v", len(nodes), err)
+	}
+}
@@ -0,0 +1,228 @@
+package doc2pdf
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gogf/gf/v2/os/gfile"
+)
+
+// DefaultCoverTemplate 默认封面模板，数据为 CoverData
+const DefaultCoverTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<style>
+	body { font-family: sans-serif; color: #222; margin: 0; }
+	.cover { min-height: 9.5in; display: flex; flex-direction: column; justify-content: center; padding: 0 64px; }
+	.logo { max-width: 240px; max-height: 120px; margin-bottom: 48px; }
+	h1 { font-size: 40px; margin: 0 0 16px; }
+	h2 { font-size: 22px; font-weight: normal; color: #555; margin: 0 0 48px; }
+	dl { display: grid; grid-template-columns: max-content 1fr; gap: 8px 24px; font-size: 14px; color: #444; margin: 0; }
+	dt { color: #888; }
+	dd { margin: 0; overflow-wrap: anywhere; }
+</style>
+</head>
+<body>
+<div class="cover">
+	{{if .Logo}}<img class="logo" src="{{.Logo}}">{{end}}
+	<h1>{{.Title}}</h1>
+	{{if .Subtitle}}<h2>{{.Subtitle}}</h2>{{end}}
+	<dl>
+		{{if .Version}}<dt>版本</dt><dd>{{.Version}}</dd>{{end}}
+		<dt>来源</dt><dd>{{.URL}}</dd>
+		<dt>导出时间</dt><dd>{{.Date}}</dd>
+		<dt>页数</dt><dd>{{.PageCount}}</dd>
+	</dl>
+</div>
+</body>
+</html>
+`
+
+// CoverOptions 封面参数
+type CoverOptions struct {
+	Title    string // 标题，默认为输出目录名
+	Subtitle string // 副标题
+	Logo     string // logo图片地址或本地文件
+	Version  string // 文档版本
+	Template string // html模板文件，优先于适配器的封面模板
+}
+
+// CoverData 封面模板数据
+type CoverData struct {
+	Title     string
+	Subtitle  string
+	Logo      template.URL // 本地文件转换为data地址
+	URL       string       // 文档入口地址
+	Version   string
+	Date      string // 导出时间
+	PageCount int    // 合并后的总页数，包括封面和目录页
+}
+
+// WithCover 在合并后的pdf开头生成封面
+//
+// createTime: 2026-10-18 10:07:54
+func WithCover(opt *CoverOptions) DocOption {
+	return func(doc *DocDownload) {
+		doc.Cover = opt
+	}
+}
+
+// RenderCoverHTML 使用模板生成封面html
+//
+// createTime: 2026-10-18 10:07:54
+func RenderCoverHTML(tpl string, data *CoverData) (string, error) {
+	t, err := template.New("cover").Parse(tpl)
+	if err != nil {
+		return "", fmt.Errorf("封面模板错误: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("封面模板错误: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// coverTemplate 封面模板，优先使用参数指定的文件，其次是适配器的模板
+//
+// createTime: 2026-10-18 10:07:54
+func (doc *DocDownload) coverTemplate() (string, error) {
+	if file := doc.Cover.Template; file != "" {
+		if !gfile.Exists(file) {
+			return "", fmt.Errorf("封面模板不存在: %s", file)
+		}
+		return gfile.GetContents(file), nil
+	}
+	if tpl := doc.Adapter.CoverTemplate(); tpl != "" {
+		return tpl, nil
+	}
+	return DefaultCoverTemplate, nil
+}
+
+// renderCover 渲染单页封面，pageCount为合并后的总页数
+//
+// createTime: 2026-10-18 10:07:54
+func (doc *DocDownload) renderCover(pageCount int) (string, error) {
+	tpl, err := doc.coverTemplate()
+	if err != nil {
+		return "", err
+	}
+	opt := doc.Cover
+	data := &CoverData{
+		Title:     opt.Title,
+		Subtitle:  opt.Subtitle,
+		URL:       doc.MainURL,
+		Version:   opt.Version,
+		Date:      time.Now().Format("2006-01-02 15:04"),
+		PageCount: pageCount,
+	}
+	if data.Title == "" {
+		data.Title = filepath.Base(doc.OutputDir())
+	}
+	if opt.Logo != "" {
+		logo, err := coverLogo(opt.Logo)
+		if err != nil {
+			return "", err
+		}
+		data.Logo = logo
+	}
+	content, err := RenderCoverHTML(tpl, data)
+	if err != nil {
+		return "", err
+	}
+	file := doc.OutputDir() + ".cover" + doc.TempSuffix
+	if err := doc.htmlToPDF(content, file, true); err != nil {
+		os.Remove(file)
+		return "", err
+	}
+	return file, nil
+}
+
+// coverLogo 本地图片转换为data地址，空白页中无法加载本地文件
+//
+// createTime: 2026-10-18 10:07:54
+func coverLogo(logo string) (template.URL, error) {
+	if !gfile.Exists(logo) {
+		return template.URL(logo), nil
+	}
+	data, err := os.ReadFile(logo)
+	if err != nil {
+		return "", err
+	}
+	mimeType := mime.TypeByExtension(filepath.Ext(logo))
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+	return template.URL("data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data)), nil
+}
+
+// frontMatter 合并时插入到开头的封面和目录页
+type frontMatter struct {
+	files   []string // 按顺序插入的文件
+	pages   int      // 插入的总页数
+	tocFrom int      // 目录页的起始页码，没有目录页时为0
+	tocThru int      // 目录页的结束页码
+}
+
+// renderFrontMatter 渲染封面和目录页，目录页的页码包含封面，封面的总页数包含目录页。
+// 生成失败时只记录日志，不影响文档合并
+//
+// createTime: 2026-10-18 10:07:54
+func (doc *DocDownload) renderFrontMatter(tree *DocTree) *frontMatter {
+	front := &frontMatter{}
+	bookmarks := tree.Bookmarks()
+	// 封面打印为单页
+	coverPages := 0
+	if doc.Cover != nil {
+		coverPages = 1
+	}
+	tocFile, tocPages := "", 0
+	renderTOC := func() {
+		if !doc.TOCPage {
+			return
+		}
+		file, pages, err := doc.renderTOC(bookmarks, coverPages)
+		if err != nil {
+			log.Println("[err]生成目录页失败:", err)
+			return
+		}
+		tocFile, tocPages = file, pages
+	}
+	renderTOC()
+	if doc.Cover != nil {
+		file, err := doc.renderCover(coverPages + tocPages + tree.PageCount())
+		if err != nil {
+			log.Println("[err]生成封面失败:", err)
+			// 目录页的页码按有封面计算，需要重新生成
+			coverPages = 0
+			if tocFile != "" {
+				os.Remove(tocFile)
+				tocFile, tocPages = "", 0
+				renderTOC()
+			}
+		} else {
+			front.files = append(front.files, file)
+		}
+	}
+	if tocFile != "" {
+		front.files = append(front.files, tocFile)
+		front.tocFrom, front.tocThru = coverPages+1, coverPages+tocPages
+	}
+	front.pages = coverPages + tocPages
+	return front
+}
+
+// remove 删除插入的临时文件
+func (f *frontMatter) remove() {
+	for _, file := range f.files {
+		os.Remove(file)
+	}
+}
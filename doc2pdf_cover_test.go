@@ -0,0 +1,75 @@
+package doc2pdf_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/hailaz/doc2pdf"
+)
+
+// TestRenderCoverHTML description
+//
+// createTime: 2026-10-18 10:07:54
+func TestRenderCoverHTML(t *testing.T) {
+	data := &doc2pdf.CoverData{
+		Title:     "GoFrame <文档>",
+		URL:       "https://goframe.org/display/gf",
+		Date:      "2026-10-19 00:48",
+		PageCount: 128,
+	}
+	html, err := doc2pdf.RenderCoverHTML(doc2pdf.DefaultCoverTemplate, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(html, "GoFrame &lt;文档&gt;") || strings.Contains(html, "<文档>") {
+		t.Error("title should be escaped")
+	}
+	if !strings.Contains(html, "https://goframe.org/display/gf") || !strings.Contains(html, "128") {
+		t.Error("missing url or page count")
+	}
+	// 未设置的字段不显示
+	if strings.Contains(html, "版本") || strings.Contains(html, "<img") || strings.Contains(html, "<h2>") {
+		t.Error("empty fields should be omitted")
+	}
+
+	data.Version = "v2.8"
+	data.Logo = "data:image/png;base64,AAAA"
+	html, err = doc2pdf.RenderCoverHTML(`<h1>{{.Title}} {{.Version}}</h1><img src="{{.Logo}}">`, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if html != `<h1>GoFrame &lt;文档&gt; v2.8</h1><img src="data:image/png;base64,AAAA">` {
+		t.Errorf("html = %s", html)
+	}
+
+	if _, err := doc2pdf.RenderCoverHTML("{{.Missing}}", data); err == nil {
+		t.Error("unknown field should fail")
+	}
+}
+
+// TestCustomConfigCover description
+//
+// createTime: 2026-10-18 10:07:54
+func TestCustomConfigCover(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "site.yaml")
+	if err := gfile.PutContents(file, "menu:\n  root: nav\ncover: cover.html\n"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := doc2pdf.LoadCustomConfig(file); err == nil {
+		t.Error("missing cover template should fail")
+	}
+	if err := gfile.PutContents(filepath.Join(dir, "cover.html"), "<h1>{{.Title}}</h1>"); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := doc2pdf.LoadCustomConfig(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	adapter := &doc2pdf.CustomAdapter{Config: cfg}
+	if cfg.Cover != filepath.Join(dir, "cover.html") || adapter.CoverTemplate() != "<h1>{{.Title}}</h1>" {
+		t.Errorf("cover = %s", cfg.Cover)
+	}
+}
@@ -0,0 +1,246 @@
+package doc2pdf
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/gogf/gf/v2/encoding/gyaml"
+	"github.com/gogf/gf/v2/os/gfile"
+)
+
+// maxExpandRounds 点击展开菜单的最大轮数，避免点击后又出现新的折叠节点时无限循环
+const maxExpandRounds = 20
+
+func init() {
+	RegisterAdapter("custom", func() SiteAdapter {
+		return &CustomAdapter{}
+	})
+}
+
+// CustomConfig 选择器配置，声明菜单结构、页面处理和打印参数，不需要为每个站点写适配器
+type CustomConfig struct {
+	Menu         CustomMenuConfig `json:"menu" yaml:"menu"`                 // 菜单
+	DownloadMain bool             `json:"downloadMain" yaml:"downloadMain"` // 是否导出入口页
+	Remove       []string         `json:"remove" yaml:"remove"`             // 打印前删除的元素选择器
+	Style        string           `json:"style" yaml:"style"`               // 打印前注入的css
+	Script       string           `json:"script" yaml:"script"`             // 打印前执行的js函数
+	Content      string           `json:"content" yaml:"content"`           // markdown模式的正文选择器
+	Strip        []string         `json:"strip" yaml:"strip"`               // markdown模式转换前删除的元素选择器
+	Print        *PrintOptions    `json:"print" yaml:"print"`               // 打印参数
+	Cover        string           `json:"cover" yaml:"cover"`               // 封面html模板文件，相对路径基于配置文件所在目录
+}
+
+// CustomMenuConfig 菜单选择器，item、link、title和children都相对于上一级元素
+type CustomMenuConfig struct {
+	Root     string             `json:"root" yaml:"root"`         // 菜单根节点
+	Item     string             `json:"item" yaml:"item"`         // 菜单项，默认为:scope > li
+	Link     string             `json:"link" yaml:"link"`         // 菜单项中的链接，默认为:scope > a
+	Title    string             `json:"title" yaml:"title"`       // 菜单项中的标题，默认使用链接文本
+	Children string             `json:"children" yaml:"children"` // 菜单项中的子菜单列表，默认为:scope > ul
+	Expand   CustomExpandConfig `json:"expand" yaml:"expand"`     // 展开折叠的菜单
+}
+
+// CustomExpandConfig 展开折叠菜单的方式，先执行script，再反复点击click直到没有匹配的元素
+type CustomExpandConfig struct {
+	Script string `json:"script" yaml:"script"` // 在菜单根节点上执行的js函数
+	Click  string `json:"click" yaml:"click"`   // 需要点击展开的元素选择器，展开后应不再匹配
+}
+
+// LoadCustomConfig 读取yaml或json配置，未设置的菜单选择器使用默认值
+//
+// createTime: 2026-10-18 09:37:22
+func LoadCustomConfig(file string) (*CustomConfig, error) {
+	if !gfile.Exists(file) {
+		return nil, fmt.Errorf("配置文件不存在: %s", file)
+	}
+	cfg := &CustomConfig{}
+	if err := gyaml.DecodeTo(gfile.GetBytes(file), cfg); err != nil {
+		return nil, fmt.Errorf("配置文件格式错误 %s: %w", file, err)
+	}
+	if cfg.Menu.Root == "" {
+		return nil, fmt.Errorf("配置文件缺少menu.root: %s", file)
+	}
+	if cfg.Menu.Item == "" {
+		cfg.Menu.Item = ":scope > li"
+	}
+	if cfg.Menu.Link == "" {
+		cfg.Menu.Link = ":scope > a"
+	}
+	if cfg.Menu.Children == "" {
+		cfg.Menu.Children = ":scope > ul"
+	}
+	if cfg.Content == "" {
+		cfg.Content = "body"
+	}
+	if cfg.Cover != "" {
+		if !filepath.IsAbs(cfg.Cover) {
+			cfg.Cover = filepath.Join(filepath.Dir(file), cfg.Cover)
+		}
+		if !gfile.Exists(cfg.Cover) {
+			return nil, fmt.Errorf("封面模板不存在: %s", cfg.Cover)
+		}
+	}
+	return cfg, nil
+}
+
+// CustomAdapter 根据选择器配置解析菜单和处理页面的通用适配器
+type CustomAdapter struct {
+	BaseAdapter
+	Config *CustomConfig
+}
+
+// Name description
+func (a *CustomAdapter) Name() string {
+	return "custom"
+}
+
+// Brief description
+func (a *CustomAdapter) Brief() string {
+	return "根据yaml选择器配置转换任意站点为pdf或markdown"
+}
+
+// Options description
+func (a *CustomAdapter) Options() []AdapterOption {
+	return []AdapterOption{
+		{Name: "config", Short: "c", Brief: "站点配置文件(yaml/json)"},
+	}
+}
+
+// SetOption description
+func (a *CustomAdapter) SetOption(name string, value string) error {
+	if name == "config" {
+		cfg, err := LoadCustomConfig(value)
+		if err != nil {
+			return err
+		}
+		a.Config = cfg
+		return nil
+	}
+	return a.BaseAdapter.SetOption(name, value)
+}
+
+// Init description
+func (a *CustomAdapter) Init(doc *DocDownload) error {
+	if a.Config == nil {
+		return fmt.Errorf("缺少站点配置，使用--config指定")
+	}
+	doc.IsDownloadMain = a.Config.DownloadMain
+	return nil
+}
+
+// MenuRootSelector description
+func (a *CustomAdapter) MenuRootSelector() string {
+	return a.Config.Menu.Root
+}
+
+// ParseMenu description
+func (a *CustomAdapter) ParseMenu(doc *DocDownload, root *rod.Element, level int, dirPath string) ([]*DocNode, error) {
+	if err := a.expandMenu(doc, root); err != nil {
+		return nil, err
+	}
+	return ParseCustomMenu(doc, root, &a.Config.Menu)
+}
+
+// expandMenu 执行展开脚本后反复点击折叠的节点，每轮点击后等待OpDelay
+//
+// createTime: 2026-10-18 09:37:22
+func (a *CustomAdapter) expandMenu(doc *DocDownload, root *rod.Element) error {
+	expand := a.Config.Menu.Expand
+	if expand.Script != "" {
+		if _, err := root.Eval(expand.Script); err != nil {
+			return fmt.Errorf("执行展开脚本失败: %w", err)
+		}
+		time.Sleep(doc.OpDelay)
+	}
+	if expand.Click == "" {
+		return nil
+	}
+	for i := 0; i < maxExpandRounds && doc.Context().Err() == nil; i++ {
+		res, err := root.Eval(`(selector) => {
+			const targets = this.querySelectorAll(selector);
+			targets.forEach(el => el.click());
+			return targets.length;
+		}`, expand.Click)
+		if err != nil {
+			return fmt.Errorf("展开菜单失败: %w", err)
+		}
+		if res.Value.Int() == 0 {
+			return nil
+		}
+		time.Sleep(doc.OpDelay)
+	}
+	return nil
+}
+
+// MarkdownOptions description
+func (a *CustomAdapter) MarkdownOptions() *MarkdownOptions {
+	return &MarkdownOptions{
+		ContentSelector: a.Config.Content,
+		Strip:           a.Config.Strip,
+	}
+}
+
+// PrintOptions description
+func (a *CustomAdapter) PrintOptions() *PrintOptions {
+	return a.Config.Print
+}
+
+// CoverTemplate description
+func (a *CustomAdapter) CoverTemplate() string {
+	if a.Config.Cover == "" {
+		return ""
+	}
+	return gfile.GetContents(a.Config.Cover)
+}
+
+// PreparePage 删除元素、注入样式、执行脚本
+//
+// createTime: 2026-10-18 09:37:22
+func (a *CustomAdapter) PreparePage(doc *DocDownload, page *rod.Page) error {
+	if len(a.Config.Remove) > 0 {
+		if err := RemoveElements(page, a.Config.Remove...); err != nil {
+			return err
+		}
+	}
+	if a.Config.Style != "" {
+		if err := AddStyle(page, a.Config.Style); err != nil {
+			return err
+		}
+	}
+	if a.Config.Script != "" {
+		if _, err := page.Eval(a.Config.Script); err != nil {
+			return err
+		}
+	}
+	WaitImages(page, 30*time.Second)
+	return nil
+}
+
+// ParseCustomMenu 按配置的选择器递归解析菜单
+//
+// createTime: 2026-10-18 09:37:22
+func ParseCustomMenu(doc *DocDownload, root *rod.Element, cfg *CustomMenuConfig) ([]*DocNode, error) {
+	res, err := root.Eval(`(cfg) => {
+		const parse = (list, depth) => Array.from(list.querySelectorAll(cfg.item)).map(item => {
+			const link = item.querySelector(cfg.link);
+			const title = cfg.title ? item.querySelector(cfg.title) : link;
+			const sub = depth < 20 ? item.querySelector(cfg.children) : null;
+			return {
+				title: title ? title.textContent.trim() : '',
+				href: link && link.href ? link.href : '',
+				children: sub ? parse(sub, depth + 1) : [],
+			};
+		});
+		return parse(this, 0);
+	}`, cfg)
+	if err != nil {
+		return nil, err
+	}
+	var items []MenuItem
+	if err := res.Value.Unmarshal(&items); err != nil {
+		return nil, err
+	}
+	return doc.menuNodes(items, make(map[string]bool)), nil
+}
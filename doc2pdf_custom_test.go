@@ -0,0 +1,59 @@
+package doc2pdf_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/hailaz/doc2pdf"
+)
+
+// TestLoadCustomConfig description
+//
+// createTime: 2026-10-18 09:37:22
+func TestLoadCustomConfig(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "site.yaml")
+	err := gfile.PutContents(file, `
+downloadMain: true
+menu:
+  root: nav.sidebar > ul
+  title: ":scope > a > span"
+  expand:
+    click: li.collapsed > button.toggle
+remove:
+  - header
+  - footer
+style: "main { max-width: none; }"
+content: article
+print:
+  paperWidth: 12
+  singlePage: true
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := doc2pdf.LoadCustomConfig(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.DownloadMain || cfg.Menu.Root != "nav.sidebar > ul" || cfg.Menu.Expand.Click != "li.collapsed > button.toggle" {
+		t.Errorf("unexpected config %+v", cfg)
+	}
+	// 未设置的选择器使用默认值
+	if cfg.Menu.Item != ":scope > li" || cfg.Menu.Link != ":scope > a" || cfg.Menu.Children != ":scope > ul" {
+		t.Errorf("unexpected menu defaults %+v", cfg.Menu)
+	}
+	if len(cfg.Remove) != 2 || cfg.Content != "article" {
+		t.Errorf("unexpected page config %+v", cfg)
+	}
+	if cfg.Print == nil || cfg.Print.PaperWidth != 12 || !cfg.Print.SinglePage {
+		t.Errorf("unexpected print options %+v", cfg.Print)
+	}
+
+	if err := gfile.PutContents(file, "content: article\n"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := doc2pdf.LoadCustomConfig(file); err == nil {
+		t.Error("config without menu.root should fail")
+	}
+}
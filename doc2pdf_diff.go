@@ -0,0 +1,537 @@
+package doc2pdf
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gogf/gf/v2/os/gfile"
+)
+
+const (
+	// DiffFormatMarkdown markdown格式的变更报告
+	DiffFormatMarkdown = "md"
+	// DiffFormatHTML html格式的变更报告
+	DiffFormatHTML = "html"
+)
+
+// diffContext 差异前后保留的上下文行数
+const diffContext = 3
+
+var indexPrefix = regexp.MustCompile(`^\d+-`)
+
+// ExportPage 导出结果中的单个页面
+type ExportPage struct {
+	Key      string // 页面标识，有清单时为页面地址，否则为去掉序号的相对路径
+	Title    string // 页面标题
+	URL      string // 页面地址
+	Location string // 去掉序号的所在目录，用于判断页面是否移动
+	File     string // 缓存的html文件
+}
+
+// DiffLine 文本差异行
+type DiffLine struct {
+	Op   byte   // ' '未变化，'+'新增，'-'删除
+	Text string // 行内容
+}
+
+// PageDiff 单个页面的差异
+type PageDiff struct {
+	Old   *ExportPage // 旧版本页面，新增页面时为nil
+	New   *ExportPage // 新版本页面，删除页面时为nil
+	Lines []DiffLine  // 正文差异，只保留变化行及其上下文
+}
+
+// Title description
+func (d *PageDiff) Title() string {
+	if d.New != nil {
+		return d.New.Title
+	}
+	return d.Old.Title
+}
+
+// DiffReport 两次导出的差异报告
+type DiffReport struct {
+	OldDir   string
+	NewDir   string
+	Added    []*PageDiff // 新增页面
+	Removed  []*PageDiff // 删除页面
+	Moved    []*PageDiff // 移动的页面，内容变化时同时出现在Modified中
+	Modified []*PageDiff // 内容变化的页面
+}
+
+// LoadExport 读取markdown模式导出目录旁缓存的html，dir为导出时的OutputDir()。
+// 存在清单时使用清单中的页面地址和标题
+//
+// createTime: 2026-10-18 09:20:35
+func LoadExport(dir string) (map[string]*ExportPage, error) {
+	dir = filepath.Clean(dir)
+	htmlDir := dir + "-html"
+	if !gfile.IsDir(htmlDir) {
+		return nil, fmt.Errorf("html缓存目录不存在: %s", htmlDir)
+	}
+	entries := make(map[string]*ManifestPage)
+	if m, err := LoadManifest(dir + ".manifest.json"); err == nil {
+		for _, entry := range m.Pages {
+			rel, err := filepath.Rel(dir, filepath.Clean(entry.File))
+			if err != nil {
+				continue
+			}
+			entries[strings.TrimSuffix(rel, filepath.Ext(rel))] = entry
+		}
+	}
+	files, err := gfile.ScanDirFile(htmlDir, "*.html", true)
+	if err != nil {
+		return nil, err
+	}
+	pages := make(map[string]*ExportPage, len(files))
+	for _, file := range files {
+		rel, err := filepath.Rel(htmlDir, file)
+		if err != nil {
+			return nil, err
+		}
+		rel = strings.TrimSuffix(rel, ".html")
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		for i, part := range parts {
+			parts[i] = indexPrefix.ReplaceAllString(part, "")
+		}
+		page := &ExportPage{
+			Key:      strings.Join(parts, "/"),
+			Title:    parts[len(parts)-1],
+			Location: strings.Join(parts[:len(parts)-1], "/"),
+			File:     file,
+		}
+		if entry, ok := entries[rel]; ok {
+			page.Key = entry.URL
+			page.URL = entry.URL
+			page.Title = entry.Title
+		}
+		pages[page.Key] = page
+	}
+	return pages, nil
+}
+
+// DiffExports 对比两次markdown模式导出的结果
+//
+// createTime: 2026-10-18 09:20:35
+func DiffExports(oldDir string, newDir string) (*DiffReport, error) {
+	oldPages, err := LoadExport(oldDir)
+	if err != nil {
+		return nil, err
+	}
+	newPages, err := LoadExport(newDir)
+	if err != nil {
+		return nil, err
+	}
+	report := &DiffReport{OldDir: oldDir, NewDir: newDir}
+	pairs := make([]*PageDiff, 0, len(newPages))
+	for _, key := range sortedKeys(newPages) {
+		if oldPage, ok := oldPages[key]; ok {
+			pairs = append(pairs, &PageDiff{Old: oldPage, New: newPages[key]})
+		} else {
+			report.Added = append(report.Added, &PageDiff{New: newPages[key]})
+		}
+	}
+	for _, key := range sortedKeys(oldPages) {
+		if _, ok := newPages[key]; !ok {
+			report.Removed = append(report.Removed, &PageDiff{Old: oldPages[key]})
+		}
+	}
+	// 没有清单时页面标识包含所在目录，标题唯一的新增和删除页面视为同一页面的移动
+	report.Added, report.Removed, pairs = matchMoved(report.Added, report.Removed, pairs)
+
+	for _, d := range pairs {
+		oldPage, newPage := d.Old, d.New
+		if oldPage.Location != newPage.Location {
+			report.Moved = append(report.Moved, d)
+		}
+		oldText, err := ExportText(oldPage.File)
+		if err != nil {
+			return nil, err
+		}
+		newText, err := ExportText(newPage.File)
+		if err != nil {
+			return nil, err
+		}
+		if oldText != newText {
+			d.Lines = DiffLines(strings.Split(oldText, "\n"), strings.Split(newText, "\n"))
+			report.Modified = append(report.Modified, d)
+		}
+	}
+	return report, nil
+}
+
+// matchMoved 按标题匹配新增和删除的页面，两边标题都唯一时认为是移动
+func matchMoved(added []*PageDiff, removed []*PageDiff, pairs []*PageDiff) ([]*PageDiff, []*PageDiff, []*PageDiff) {
+	count := func(diffs []*PageDiff) map[string]int {
+		titles := make(map[string]int)
+		for _, d := range diffs {
+			titles[d.Title()]++
+		}
+		return titles
+	}
+	addedTitles, removedTitles := count(added), count(removed)
+	oldByTitle := make(map[string]*PageDiff)
+	restRemoved := make([]*PageDiff, 0, len(removed))
+	for _, d := range removed {
+		if removedTitles[d.Title()] == 1 && addedTitles[d.Title()] == 1 {
+			oldByTitle[d.Title()] = d
+		} else {
+			restRemoved = append(restRemoved, d)
+		}
+	}
+	restAdded := make([]*PageDiff, 0, len(added))
+	for _, d := range added {
+		if old, ok := oldByTitle[d.Title()]; ok {
+			pairs = append(pairs, &PageDiff{Old: old.Old, New: d.New})
+		} else {
+			restAdded = append(restAdded, d)
+		}
+	}
+	return restAdded, restRemoved, pairs
+}
+
+// sortedKeys description
+func sortedKeys(pages map[string]*ExportPage) []string {
+	keys := make([]string, 0, len(pages))
+	for key := range pages {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ExportText 将缓存的html转换为markdown文本，用于按行对比
+//
+// createTime: 2026-10-18 09:20:35
+func ExportText(file string) (string, error) {
+	text, err := NewMarkdownConverter().ConvertString(gfile.GetContents(file))
+	if err != nil {
+		return "", err
+	}
+	lines := make([]string, 0)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t")
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// DiffLines 基于Myers算法的按行对比，只保留变化行及前后diffContext行，
+// 不相邻的片段之间使用"..."分隔
+//
+// createTime: 2026-10-18 09:20:35
+func DiffLines(a []string, b []string) []DiffLine {
+	all := diffRange(a, b, make([]DiffLine, 0, len(a)+len(b)))
+	// 连续的变化行中删除行在前，新增行在后
+	for start := 0; start < len(all); {
+		if all[start].Op == ' ' {
+			start++
+			continue
+		}
+		end := start
+		for end < len(all) && all[end].Op != ' ' {
+			end++
+		}
+		sort.SliceStable(all[start:end], func(i, j int) bool {
+			return all[start+i].Op == '-' && all[start+j].Op == '+'
+		})
+		start = end
+	}
+
+	// 只保留变化行的上下文
+	keep := make([]bool, len(all))
+	for k, line := range all {
+		if line.Op == ' ' {
+			continue
+		}
+		for c := max(0, k-diffContext); c <= min(len(all)-1, k+diffContext); c++ {
+			keep[c] = true
+		}
+	}
+	lines := make([]DiffLine, 0)
+	for k, line := range all {
+		if !keep[k] {
+			continue
+		}
+		if k > 0 && !keep[k-1] && len(lines) > 0 {
+			lines = append(lines, DiffLine{Op: ' ', Text: "..."})
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// diffRange 对比a和b并把结果追加到lines。去掉相同的首尾行后用Myers的线性空间算法
+// 找到两条最短路径的交点，再分别对比交点前后两部分，内存与行数成正比
+//
+// createTime: 2026-10-18 09:20:35
+func diffRange(a []string, b []string, lines []DiffLine) []DiffLine {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		lines = append(lines, DiffLine{Op: ' ', Text: a[prefix]})
+		prefix++
+	}
+	a, b = a[prefix:], b[prefix:]
+	suffix := 0
+	for suffix < len(a) && suffix < len(b) && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	common := a[len(a)-suffix:]
+	a, b = a[:len(a)-suffix], b[:len(b)-suffix]
+
+	x, y, ok := 0, 0, false
+	if len(a) > 0 && len(b) > 0 {
+		x, y, ok = middleSnake(a, b)
+	}
+	if ok {
+		lines = diffRange(a[:x], b[:y], lines)
+		lines = diffRange(a[x:], b[y:], lines)
+	} else {
+		for _, text := range a {
+			lines = append(lines, DiffLine{Op: '-', Text: text})
+		}
+		for _, text := range b {
+			lines = append(lines, DiffLine{Op: '+', Text: text})
+		}
+	}
+	for _, text := range common {
+		lines = append(lines, DiffLine{Op: ' ', Text: text})
+	}
+	return lines
+}
+
+// middleSnake 同时从起点和终点沿对角线搜索最短编辑路径，返回两条路径的交点，
+// 没有公共行时ok为false
+//
+// createTime: 2026-10-18 09:20:35
+func middleSnake(a []string, b []string) (x int, y int, ok bool) {
+	n, m := len(a), len(b)
+	maxD := (n + m + 1) / 2
+	offset := maxD + 1
+	// forward[offset+k]、backward[offset+k]为对角线k上已到达的最远位置
+	forward := make([]int, 2*offset+1)
+	backward := make([]int, 2*offset+1)
+	for i := range forward {
+		forward[i], backward[i] = -1, -1
+	}
+	forward[offset+1], backward[offset+1] = 0, 0
+	delta := n - m
+	// 总行数为奇数时正向路径先到达交点，否则反向路径先到达
+	odd := delta%2 != 0
+	var fStart, fEnd, bStart, bEnd int
+	for d := 0; d < maxD; d++ {
+		for k := -d + fStart; k <= d-fEnd; k += 2 {
+			i := offset + k
+			var x1 int
+			if k == -d || (k != d && forward[i-1] < forward[i+1]) {
+				x1 = forward[i+1]
+			} else {
+				x1 = forward[i-1] + 1
+			}
+			y1 := x1 - k
+			for x1 < n && y1 < m && a[x1] == b[y1] {
+				x1++
+				y1++
+			}
+			forward[i] = x1
+			switch {
+			case x1 > n:
+				fEnd += 2
+			case y1 > m:
+				fStart += 2
+			case odd:
+				if j := offset + delta - k; j >= 0 && j < len(backward) && backward[j] != -1 && x1 >= n-backward[j] {
+					return x1, y1, splitValid(x1, y1, n, m)
+				}
+			}
+		}
+		for k := -d + bStart; k <= d-bEnd; k += 2 {
+			i := offset + k
+			var x2 int
+			if k == -d || (k != d && backward[i-1] < backward[i+1]) {
+				x2 = backward[i+1]
+			} else {
+				x2 = backward[i-1] + 1
+			}
+			y2 := x2 - k
+			for x2 < n && y2 < m && a[n-x2-1] == b[m-y2-1] {
+				x2++
+				y2++
+			}
+			backward[i] = x2
+			switch {
+			case x2 > n:
+				bEnd += 2
+			case y2 > m:
+				bStart += 2
+			case !odd:
+				if j := offset + delta - k; j >= 0 && j < len(forward) && forward[j] != -1 {
+					x1 := forward[j]
+					y1 := offset + x1 - j
+					if x1 >= n-x2 {
+						return x1, y1, splitValid(x1, y1, n, m)
+					}
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// splitValid 交点在起点或终点时无法继续拆分
+func splitValid(x int, y int, n int, m int) bool {
+	return !(x == 0 && y == 0) && !(x == n && y == m)
+}
+
+// Render 按格式生成报告
+//
+// createTime: 2026-10-18 09:20:35
+func (r *DiffReport) Render(format string) (string, error) {
+	switch format {
+	case DiffFormatMarkdown:
+		return r.Markdown(), nil
+	case DiffFormatHTML:
+		return r.HTML(), nil
+	}
+	return "", fmt.Errorf("不支持的报告格式: %s", format)
+}
+
+// String description
+func (r *DiffReport) String() string {
+	return fmt.Sprintf("新增%d页，删除%d页，移动%d页，修改%d页", len(r.Added), len(r.Removed), len(r.Moved), len(r.Modified))
+}
+
+// Markdown 生成markdown格式的报告，修改的页面附带diff代码块
+//
+// createTime: 2026-10-18 09:20:35
+func (r *DiffReport) Markdown() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# 文档变更\n\n`%s` → `%s`\n\n%s\n", r.OldDir, r.NewDir, r)
+	writeList := func(title string, diffs []*PageDiff, item func(d *PageDiff) string) {
+		if len(diffs) == 0 {
+			return
+		}
+		fmt.Fprintf(&buf, "\n## %s\n\n", title)
+		for _, d := range diffs {
+			buf.WriteString("- " + item(d) + "\n")
+		}
+	}
+	writeList("新增", r.Added, func(d *PageDiff) string { return markdownLink(d.New) })
+	writeList("删除", r.Removed, func(d *PageDiff) string { return markdownLink(d.Old) })
+	writeList("移动", r.Moved, func(d *PageDiff) string {
+		return fmt.Sprintf("%s：`%s` → `%s`", markdownLink(d.New), d.Old.Location, d.New.Location)
+	})
+	if len(r.Modified) > 0 {
+		buf.WriteString("\n## 修改\n")
+		for _, d := range r.Modified {
+			fence := markdownFence(d.Lines)
+			fmt.Fprintf(&buf, "\n### %s\n\n%sdiff\n", d.Title(), fence)
+			for _, line := range d.Lines {
+				buf.WriteString(string(line.Op) + line.Text + "\n")
+			}
+			buf.WriteString(fence + "\n")
+		}
+	}
+	return buf.String()
+}
+
+// markdownFence 代码块的围栏，比内容中最长的连续反引号多一个，至少3个
+//
+// createTime: 2026-10-18 09:20:35
+func markdownFence(lines []DiffLine) string {
+	longest := 0
+	for _, line := range lines {
+		run := 0
+		for _, r := range line.Text {
+			if r != '`' {
+				run = 0
+				continue
+			}
+			run++
+			longest = max(longest, run)
+		}
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
+
+// markdownLink description
+func markdownLink(page *ExportPage) string {
+	if page.URL == "" {
+		return page.Key
+	}
+	return fmt.Sprintf("[%s](%s)", page.Title, page.URL)
+}
+
+// HTML 生成html格式的报告
+//
+// createTime: 2026-10-18 09:20:35
+func (r *DiffReport) HTML() string {
+	var buf bytes.Buffer
+	buf.WriteString(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>文档变更</title>
+<style>
+body { font-family: sans-serif; max-width: 960px; margin: 0 auto; }
+pre { background: #f6f8fa; padding: 8px; white-space: pre-wrap; }
+.add { background: #e6ffed; display: block; }
+.del { background: #ffeef0; display: block; }
+</style>
+</head>
+<body>
+`)
+	fmt.Fprintf(&buf, "<h1>文档变更</h1>\n<p><code>%s</code> → <code>%s</code></p>\n<p>%s</p>\n",
+		html.EscapeString(r.OldDir), html.EscapeString(r.NewDir), html.EscapeString(r.String()))
+	writeList := func(title string, diffs []*PageDiff, item func(d *PageDiff) string) {
+		if len(diffs) == 0 {
+			return
+		}
+		fmt.Fprintf(&buf, "<h2>%s</h2>\n<ul>\n", title)
+		for _, d := range diffs {
+			buf.WriteString("<li>" + item(d) + "</li>\n")
+		}
+		buf.WriteString("</ul>\n")
+	}
+	writeList("新增", r.Added, func(d *PageDiff) string { return htmlLink(d.New) })
+	writeList("删除", r.Removed, func(d *PageDiff) string { return htmlLink(d.Old) })
+	writeList("移动", r.Moved, func(d *PageDiff) string {
+		return fmt.Sprintf("%s：<code>%s</code> → <code>%s</code>", htmlLink(d.New),
+			html.EscapeString(d.Old.Location), html.EscapeString(d.New.Location))
+	})
+	if len(r.Modified) > 0 {
+		buf.WriteString("<h2>修改</h2>\n")
+		for _, d := range r.Modified {
+			fmt.Fprintf(&buf, "<h3>%s</h3>\n<pre>", html.EscapeString(d.Title()))
+			for _, line := range d.Lines {
+				text := html.EscapeString(string(line.Op) + line.Text)
+				switch line.Op {
+				case '+':
+					buf.WriteString(`<span class="add">` + text + "</span>")
+				case '-':
+					buf.WriteString(`<span class="del">` + text + "</span>")
+				default:
+					buf.WriteString(text + "\n")
+				}
+			}
+			buf.WriteString("</pre>\n")
+		}
+	}
+	buf.WriteString("</body>\n</html>\n")
+	return buf.String()
+}
+
+// htmlLink description
+func htmlLink(page *ExportPage) string {
+	if page.URL == "" {
+		return html.EscapeString(page.Key)
+	}
+	return fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(page.URL), html.EscapeString(page.Title))
+}
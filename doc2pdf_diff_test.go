@@ -0,0 +1,128 @@
+package doc2pdf_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/hailaz/doc2pdf"
+)
+
+// TestDiffLines description
+//
+// createTime: 2026-10-18 09:20:35
+func TestDiffLines(t *testing.T) {
+	a := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+	b := []string{"a", "b", "c", "d", "e", "F", "g", "h", "i", "j", "k"}
+	var got []string
+	for _, line := range doc2pdf.DiffLines(a, b) {
+		got = append(got, string(line.Op)+line.Text)
+	}
+	want := []string{" c", " d", " e", "-f", "+F", " g", " h", " i", " j", "+k"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	// 不相邻的变化之间使用...分隔
+	a = []string{"-", "1", "2", "3", "4", "5", "6", "7", "8", "-"}
+	b = []string{"+", "1", "2", "3", "4", "5", "6", "7", "8", "+"}
+	got = got[:0]
+	for _, line := range doc2pdf.DiffLines(a, b) {
+		got = append(got, string(line.Op)+line.Text)
+	}
+	want = []string{"--", "++", " 1", " 2", " 3", " ...", " 6", " 7", " 8", "--", "++"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+// TestDiffLinesLarge 大文件只有少量变化时不会按行数的平方分配内存
+//
+// createTime: 2026-10-18 09:20:35
+func TestDiffLinesLarge(t *testing.T) {
+	a := make([]string, 200000)
+	for i := range a {
+		a[i] = fmt.Sprintf("line %d", i)
+	}
+	b := append([]string{}, a...)
+	b[100000] = "changed"
+	b = append(b, "appended")
+	var got []string
+	for _, line := range doc2pdf.DiffLines(a, b) {
+		if line.Op != ' ' {
+			got = append(got, string(line.Op)+line.Text)
+		}
+	}
+	want := []string{"-line 100000", "+changed", "+appended"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+// TestDiffMarkdownFence 正文中有代码块时diff代码块使用更长的围栏
+//
+// createTime: 2026-10-18 09:20:35
+func TestDiffMarkdownFence(t *testing.T) {
+	report := &doc2pdf.DiffReport{Modified: []*doc2pdf.PageDiff{{
+		New:   &doc2pdf.ExportPage{Title: "Page"},
+		Lines: doc2pdf.DiffLines([]string{"```go", "a := 1", "```"}, []string{"````go", "a := 2", "````"}),
+	}}}
+	md := report.Markdown()
+	if !strings.Contains(md, "\n`````diff\n") || !strings.HasSuffix(md, "\n`````\n") {
+		t.Errorf("markdown = %s", md)
+	}
+}
+
+// TestDiffExports description
+//
+// createTime: 2026-10-18 09:20:35
+func TestDiffExports(t *testing.T) {
+	dir := t.TempDir()
+	oldDir := filepath.Join(dir, "v1-md")
+	newDir := filepath.Join(dir, "v2-md")
+	files := map[string]string{
+		oldDir + "-html/0-快速开始/0-安装.html": "<h1>安装</h1><p>go get</p>",
+		oldDir + "-html/1-旧功能.html":       "<p>removed</p>",
+		oldDir + "-html/2-配置.html":        "<p>config</p>",
+		newDir + "-html/0-快速开始/0-安装.html": "<h1>安装</h1><p>go install</p>",
+		newDir + "-html/1-进阶/0-配置.html":   "<p>config</p>",
+		newDir + "-html/2-新功能.html":       "<p>added</p>",
+	}
+	for file, contents := range files {
+		if err := gfile.PutContents(file, contents); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := doc2pdf.DiffExports(oldDir, newDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := report.String(); got != "新增1页，删除1页，移动1页，修改1页" {
+		t.Fatalf("String: got %s", got)
+	}
+	if report.Added[0].New.Key != "新功能" || report.Removed[0].Old.Key != "旧功能" {
+		t.Errorf("unexpected added/removed: %s, %s", report.Added[0].New.Key, report.Removed[0].Old.Key)
+	}
+	if moved := report.Moved[0]; moved.Old.Location != "" || moved.New.Location != "进阶" {
+		t.Errorf("unexpected moved: %+v", moved.New)
+	}
+	md, err := report.Render(doc2pdf.DiffFormatMarkdown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"### 安装", "-go get", "+go install"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown: missing %q in\n%s", want, md)
+		}
+	}
+	htmlReport, err := report.Render(doc2pdf.DiffFormatHTML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(htmlReport, `<span class="add">+go install</span>`) {
+		t.Errorf("html: unexpected\n%s", htmlReport)
+	}
+}
@@ -0,0 +1,309 @@
+package doc2pdf
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/go-rod/rod"
+	"github.com/gogf/gf/v2/os/gfile"
+)
+
+// hashRouteAnchor docsify标题锚点参数
+var hashRouteAnchor = regexp.MustCompile(`\?id=[^&]*&?`)
+
+// DownloadDocsify 下载docsify文档，mode为pdf或md
+//
+// createTime: 2026-10-18 09:33:26
+func DownloadDocsify(ctx context.Context, mainURL string, outputDir string, mode string, opts ...DocOption) (*RunSummary, error) {
+	return DownloadWithAdapter(ctx, &DocsifyAdapter{}, mainURL, outputDir, mode, opts...)
+}
+
+func init() {
+	RegisterAdapter("docsify", func() SiteAdapter {
+		return &DocsifyAdapter{}
+	})
+}
+
+// DocsifyAdapter docsify和其他使用hash路由(#/)的单页应用适配器。
+// pdf模式下所有页面在同一个标签页中切换路由，等待正文容器变化后打印
+type DocsifyAdapter struct {
+	BaseAdapter
+	Menu    string // 菜单根节点选择器
+	Content string // 正文容器选择器
+}
+
+// Name description
+func (a *DocsifyAdapter) Name() string {
+	return "docsify"
+}
+
+// Brief description
+func (a *DocsifyAdapter) Brief() string {
+	return "docsify等hash路由(#/)的单页应用文档转换为pdf或markdown"
+}
+
+// Options description
+func (a *DocsifyAdapter) Options() []AdapterOption {
+	return []AdapterOption{
+		{Name: "menu", Brief: "菜单根节点选择器，默认为docsify的侧边栏"},
+		{Name: "content", Brief: "正文容器选择器，默认为docsify的#main"},
+	}
+}
+
+// SetOption description
+func (a *DocsifyAdapter) SetOption(name string, value string) error {
+	switch name {
+	case "menu":
+		a.Menu = value
+	case "content":
+		a.Content = value
+	default:
+		return a.BaseAdapter.SetOption(name, value)
+	}
+	return nil
+}
+
+// MenuRootSelector description
+func (a *DocsifyAdapter) MenuRootSelector() string {
+	if a.Menu != "" {
+		return a.Menu
+	}
+	return "aside.sidebar div.sidebar-nav"
+}
+
+// contentSelector description
+func (a *DocsifyAdapter) contentSelector() string {
+	if a.Content != "" {
+		return a.Content
+	}
+	return "article#main, article.markdown-section"
+}
+
+// ParseMenu description
+func (a *DocsifyAdapter) ParseMenu(doc *DocDownload, root *rod.Element, level int, dirPath string) ([]*DocNode, error) {
+	menu, err := menuSelection(root)
+	if err != nil {
+		return nil, err
+	}
+	return doc.menuNodes(ParseHashRouteMenu(menu), make(map[string]bool)), nil
+}
+
+// MarkdownOptions description
+func (a *DocsifyAdapter) MarkdownOptions() *MarkdownOptions {
+	return &MarkdownOptions{
+		ContentSelector: a.contentSelector(),
+		Strip: []string{
+			"div.docsify-pagination-container",
+			"div.docsify-copy-code-button",
+			"button.docsify-copy-code-button",
+			"#gitalk-container",
+		},
+	}
+}
+
+// PrintOptions description
+func (a *DocsifyAdapter) PrintOptions() *PrintOptions {
+	return &PrintOptions{PaperWidth: 15, PageHeight: 11, SinglePage: true}
+}
+
+// Fingerprint hash路由的页面共用同一个html，响应头无法区分页面，只使用正文hash
+func (a *DocsifyAdapter) Fingerprint(doc *DocDownload, pageURL string) (string, error) {
+	return doc.ContentFingerprint(pageURL, a.contentSelector())
+}
+
+// PreparePage description
+//
+// createTime: 2026-10-18 09:33:26
+func (a *DocsifyAdapter) PreparePage(doc *DocDownload, page *rod.Page) error {
+	if err := a.hideChrome(page); err != nil {
+		return err
+	}
+	WaitImages(page, 30*time.Second)
+	return nil
+}
+
+// hideChrome 隐藏侧边栏、导航、搜索和评论，正文铺满页面。
+// 切换路由时docsify会重新渲染侧边栏，只能隐藏不能删除
+//
+// createTime: 2026-10-18 09:33:26
+func (a *DocsifyAdapter) hideChrome(page *rod.Page) error {
+	return AddStyle(page, `
+		aside.sidebar, button.sidebar-toggle, nav.app-nav, a.github-corner, div.progress,
+		div.docsify-pagination-container, #gitalk-container { display: none !important; }
+		section.content { position: static !important; left: 0 !important; padding-top: 0 !important; }
+		.markdown-section { max-width: none !important; padding: 0 1rem !important; }
+		pre, pre code { white-space: pre-wrap !important; overflow-wrap: anywhere; }
+	`)
+}
+
+// Exporter pdf模式下在同一个标签页中切换路由，恢复和增量导出需要逐页校验，不使用
+func (a *DocsifyAdapter) Exporter(doc *DocDownload) Exporter {
+	if doc.Mode != DocDownloadModePDF || doc.Resume || doc.Incremental {
+		return nil
+	}
+	return &hashRouteExporter{adapter: a}
+}
+
+// hashRouteExporter 先在一个标签页中渲染全部页面，再交给pdf导出器合并
+type hashRouteExporter struct {
+	adapter *DocsifyAdapter
+}
+
+// Export 单标签页渲染失败的页面由pdf导出器逐页重新渲染
+//
+// createTime: 2026-10-18 09:33:26
+func (e *hashRouteExporter) Export(doc *DocDownload, tree *DocTree) error {
+	if err := e.renderRoutes(doc, tree); err != nil {
+		log.Println("[err]单标签页渲染失败，逐页渲染:", err)
+	}
+	return (&PDFExporter{}).Export(doc, tree)
+}
+
+// renderRoutes 按文档树顺序切换路由并打印
+//
+// createTime: 2026-10-18 09:33:26
+func (e *hashRouteExporter) renderRoutes(doc *DocDownload, tree *DocTree) error {
+	main, err := url.Parse(doc.MainURL)
+	if err != nil {
+		return err
+	}
+	main.Fragment = ""
+	main.RawFragment = ""
+	page, err := doc.openPage(doc.MainURL, 0)
+	if err != nil {
+		return err
+	}
+	defer doc.closePage(page, 0)
+	if err := e.adapter.hideChrome(page); err != nil {
+		return err
+	}
+	opt := e.adapter.PrintOptions()
+	for _, node := range tree.Pages() {
+		if err := doc.Context().Err(); err != nil {
+			return err
+		}
+		if gfile.Exists(node.Path) {
+			continue
+		}
+		u, err := url.Parse(node.URL)
+		if err != nil {
+			continue
+		}
+		route := u.Fragment
+		u.Fragment = ""
+		u.RawFragment = ""
+		// 不在同一个html中的页面只能单独打开
+		if u.String() != main.String() || !IsHashRoute(route) {
+			continue
+		}
+		if err := e.navigate(doc, page, "#"+route); err != nil {
+			log.Printf("[err]切换路由失败 %s: %s", node.URL, err)
+			continue
+		}
+		WaitImages(page, 30*time.Second)
+		if err := os.MkdirAll(path.Dir(node.Path), os.ModePerm); err != nil {
+			return err
+		}
+		log.Println("渲染路由", node.Title, node.Path)
+		if err := PageToPDFWithOptions(page, node.Path, opt); err != nil {
+			log.Printf("[err]打印页面失败 %s: %s", node.Path, err)
+			os.Remove(node.Path)
+		}
+	}
+	return nil
+}
+
+// navigate 修改location.hash切换路由，等待正文容器被替换或内容变化后再等待DOM稳定
+//
+// createTime: 2026-10-18 09:33:26
+func (e *hashRouteExporter) navigate(doc *DocDownload, page *rod.Page, route string) error {
+	timeout := doc.PageTimeout
+	if timeout <= 0 {
+		timeout = time.Minute
+	}
+	res, err := page.Eval(`async (route, selector, timeout) => {
+		const old = document.querySelector(selector);
+		const oldHTML = old ? old.innerHTML : '';
+		if ((location.hash || '#/') === route && old) {
+			return true;
+		}
+		location.hash = route;
+		const start = Date.now();
+		while (Date.now() - start < timeout) {
+			await new Promise(resolve => setTimeout(resolve, 100));
+			const current = document.querySelector(selector);
+			if (current && (current !== old || current.innerHTML !== oldHTML)) {
+				window.scrollTo(0, 0);
+				return true;
+			}
+		}
+		return false;
+	}`, route, e.adapter.contentSelector(), timeout.Milliseconds())
+	if err != nil {
+		return err
+	}
+	if !res.Value.Bool() {
+		return fmt.Errorf("等待正文变化超时: %s", route)
+	}
+	return page.WaitDOMStable(time.Second, 0.01)
+}
+
+// ParseHashRouteMenu 解析嵌套列表形式的侧边栏，li中第一个非列表元素作为标题，
+// docsify自动生成的标题锚点(?id=)不作为页面
+//
+// createTime: 2026-10-18 09:33:26
+func ParseHashRouteMenu(root *goquery.Selection) []MenuItem {
+	var parse func(list *goquery.Selection) []MenuItem
+	parse = func(list *goquery.Selection) []MenuItem {
+		items := make([]MenuItem, 0)
+		list.ChildrenFiltered("li").Each(func(_ int, li *goquery.Selection) {
+			item := MenuItem{}
+			if head := li.Children().Not("ul, ol").First(); head.Length() > 0 {
+				item.Title = menuText(head)
+				link := head
+				if !head.Is("a") {
+					link = head.Find("a").First()
+				}
+				item.Href = hashRouteHref(menuHref(link))
+			} else {
+				// 分组标题可能是li中的文本节点
+				item.Title = strings.Join(strings.Fields(li.Contents().FilterFunction(func(_ int, s *goquery.Selection) bool {
+					return goquery.NodeName(s) == "#text"
+				}).Text()), " ")
+			}
+			if sub := li.ChildrenFiltered("ul:not(.app-sub-sidebar), ol").First(); sub.Length() > 0 {
+				item.Children = parse(sub)
+			}
+			items = append(items, item)
+		})
+		return items
+	}
+	list := root
+	if !root.Is("ul, ol") {
+		list = root.Find("ul, ol").First()
+	}
+	if list.Length() == 0 {
+		return nil
+	}
+	return parse(list)
+}
+
+// hashRouteHref 去掉hash路由中docsify标题锚点的?id=参数
+//
+// createTime: 2026-10-18 09:33:26
+func hashRouteHref(href string) string {
+	i := strings.Index(href, "#")
+	if i < 0 {
+		return href
+	}
+	route := hashRouteAnchor.ReplaceAllString(href[i:], "?")
+	return href[:i] + strings.TrimSuffix(route, "?")
+}
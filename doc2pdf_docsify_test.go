@@ -0,0 +1,112 @@
+package doc2pdf_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hailaz/doc2pdf"
+)
+
+// TestIsHashRoute description
+//
+// createTime: 2026-10-18 09:33:26
+func TestIsHashRoute(t *testing.T) {
+	cases := map[string]bool{
+		"":              false,
+		"section":       false,
+		"/":             true,
+		"/guide/start":  true,
+		"!/quickstart":  true,
+		"install-guide": false,
+	}
+	for fragment, want := range cases {
+		if got := doc2pdf.IsHashRoute(fragment); got != want {
+			t.Errorf("IsHashRoute(%q) = %v, want %v", fragment, got, want)
+		}
+	}
+}
+
+// docsifyFixture 侧边栏使用hash路由，切换路由时只替换正文容器
+const docsifyFixture = `<html><body>
+<aside class="sidebar"><div class="sidebar-nav"><ul>
+	<li><a href="#/">Home</a></li>
+	<li><p>Guide</p><ul>
+		<li><a href="#/guide/start">Start</a>
+			<ul class="app-sub-sidebar"><li><a href="#/guide/start?id=install">Install</a></li></ul>
+		</li>
+		<li><a href="#/guide/config?id=top">Config</a></li>
+	</ul></li>
+	<li>API<ul><li><a href="#/api">Reference</a></li></ul></li>
+</ul></div></aside>
+<section class="content"><article id="main" class="markdown-section"></article></section>
+<script>
+	const pages = {
+		'/': '<h1>Home</h1>',
+		'/guide/start': '<h1>Start</h1>',
+		'/guide/config': '<h1>Config</h1>',
+		'/api': '<h1>Reference</h1>',
+	};
+	const render = () => {
+		const route = (location.hash.slice(1) || '/').split('?')[0];
+		document.getElementById('main').innerHTML = pages[route] || '<h1>404</h1>';
+	};
+	window.addEventListener('hashchange', render);
+	render();
+</script>
+</body></html>`
+
+// TestParseHashRouteMenu 分组标题可以是文本节点，去掉标题锚点参数，跳过标题目录
+//
+// createTime: 2026-10-18 09:33:26
+func TestParseHashRouteMenu(t *testing.T) {
+	items := doc2pdf.ParseHashRouteMenu(menuFixture(t, docsifyFixture, (&doc2pdf.DocsifyAdapter{}).MenuRootSelector()))
+	if got := menuTitles(items); got != "Home Guide[Start Config] API[Reference]" {
+		t.Fatalf("menu = %s", got)
+	}
+	guide := items[1]
+	if guide.Href != "" || guide.Children[0].Href != "#/guide/start" || guide.Children[1].Href != "#/guide/config" {
+		t.Errorf("guide = %q, start = %q, config = %q", guide.Href, guide.Children[0].Href, guide.Children[1].Href)
+	}
+}
+
+// TestDocsifyMenu 解析hash路由的侧边栏，分组标题不是页面，标题锚点不作为页面
+//
+// createTime: 2026-10-18 09:33:26
+func TestDocsifyMenu(t *testing.T) {
+	doc := newFixtureDoc(t, &doc2pdf.DocsifyAdapter{}, map[string]string{"/": docsifyFixture}, "/")
+	tree, err := doc.Discover(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := treeTitles(tree.Nodes); got != "Home Guide[Start Config] API[Reference]" {
+		t.Fatalf("tree = %s", got)
+	}
+	guide := tree.Nodes[1]
+	if guide.URL != "" || guide.Children[0].URL != doc.MainURL+"#/guide/start" || guide.Children[1].URL != doc.MainURL+"#/guide/config" {
+		t.Errorf("guide = %q, start = %s, config = %s", guide.URL, guide.Children[0].URL, guide.Children[1].URL)
+	}
+}
+
+// TestDocsifyExport pdf模式下所有路由在同一个标签页中渲染，不再逐页打开
+//
+// createTime: 2026-10-18 09:33:26
+func TestDocsifyExport(t *testing.T) {
+	doc, site := newFixtureSite(t, &doc2pdf.DocsifyAdapter{}, map[string]string{"/": docsifyFixture}, "/")
+	tree, err := doc.Discover(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := doc.Exporter().(*doc2pdf.PDFExporter); ok {
+		t.Fatal("hash routes should be rendered in a single tab")
+	}
+	menuRequests := site.Requests("/")
+	exportFixture(t, doc, tree)
+	if n := site.Requests("/") - menuRequests; n != 1 {
+		t.Errorf("index requests during export = %d", n)
+	}
+
+	doc.Resume = true
+	if _, ok := doc.Exporter().(*doc2pdf.PDFExporter); !ok {
+		t.Errorf("resume should render page by page, exporter = %#v", doc.Exporter())
+	}
+}
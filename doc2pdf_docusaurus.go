@@ -2,6 +2,7 @@ package doc2pdf
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"path"
@@ -11,9 +12,6 @@ import (
 
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/proto"
-	"github.com/go-rod/rod/lib/utils"
-	"github.com/pdfcpu/pdfcpu/pkg/api"
-	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
 )
 
 // DownloadHailaz description
@@ -21,178 +19,170 @@ import (
 // createTime: 2023-07-28 15:21:19
 //
 // author: hailaz
-func DownloadHailaz() {
+func DownloadHailaz(ctx context.Context) (*RunSummary, error) {
 	// http://www.hailaz.cn/docs/learn/index
 	// DownloadDocusaurus("http://www.hailaz.cn/docs/learn/index", "./output/hailaz-learn")
-	DownloadDocusaurus("https://www.hailaz.cn/docs/live/", "./output/hailaz-live")
+	return DownloadDocusaurus(ctx, "https://www.hailaz.cn/docs/live/", "./output/hailaz-live", DocDownloadModePDF)
 }
 
 // DownloadGoFrame 下载GoFrame文档
-func DownloadGoFrame(domain string) {
+func DownloadGoFrame(ctx context.Context, domain string, mode string, opts ...DocOption) error {
 	if domain == "" {
 		domain = "https://pages.goframe.org"
 	}
-	if false {
-		DownloadDocusaurus(domain+"/release/note", "./output/goframe/release")
-		DownloadDocusaurus(domain+"/quick/install", "./output/goframe/quick")
-		DownloadDocusaurus(domain+"/examples/grpc", "./output/goframe/examples")
-		DownloadDocusaurus(domain+"/docs/cli", "./output/goframe/docs")
-	} else {
-		var wg sync.WaitGroup
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			DownloadDocusaurus(domain+"/docs/cli", "./output/goframe/docs")
-		}()
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			DownloadDocusaurus(domain+"/quick/install", "./output/goframe/quick")
-		}()
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			DownloadDocusaurus(domain+"/examples/grpc", "./output/goframe/examples")
-		}()
+	// 并行的导出共用一个浏览器，调用方没有传入WithBrowser时按调用方的浏览器参数启动一个
+	probe := &DocDownload{}
+	for _, opt := range opts {
+		opt(probe)
+	}
+	if probe.sharedBrowser == nil {
+		browser := NewBrowser(probe.browserOptions)
+		defer browser.Close()
+		opts = append(opts, WithBrowser(browser))
+	}
+	tasks := map[string]string{
+		domain + "/docs/cli":      "./output/goframe/docs",
+		domain + "/quick/install": "./output/goframe/quick",
+		domain + "/examples/grpc": "./output/goframe/examples",
+		domain + "/release/note":  "./output/goframe/release",
+	}
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for mainURL, outputDir := range tasks {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			DownloadDocusaurus(domain+"/release/note", "./output/goframe/release")
+			if _, err := DownloadDocusaurus(ctx, mainURL, outputDir, mode, opts...); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
 		}()
-		wg.Wait()
 	}
-
+	wg.Wait()
+	return errors.Join(errs...)
 }
 
-// DownloadDocusaurus 下载confluence文档
+// DownloadDocusaurus 下载docusaurus文档，mode为pdf或md
 //
 // createTime: 2023-07-27 15:26:56
 //
 // author: hailaz
-func DownloadDocusaurus(mainURL string, outputDir string) {
-	doc := NewDocDownload(mainURL, outputDir)
-	doc.PageToPDF = func(page *rod.Page, filePath string) error {
-		var width float64 = 20
-		req := &proto.PagePrintToPDF{
-			PrintBackground: true,
-			PaperWidth:      &width,
-		}
-
-		err := PageToPDFWithCfg(page, filePath, req)
-		if err != nil {
-			return err
-		}
-		// 获取页数，合并成单页
-		pageCount, err := api.PageCountFile(filePath)
-		if err == nil {
-			height := 11 * float64(pageCount)
-			req.PaperHeight = &height
-			return PageToPDFWithCfg(page, filePath, req)
-		}
-
-		return nil
-	}
-	doc.SavePDFBefore = func(page *rod.Page) {
-		// #__docusaurus_skipToContent_fallback > div > div > main > div > div > div.col.docItemCol_VOVn
-		// 删除指定class的样式
-		page.MustEval(`() => {
-			const elements = document.getElementsByClassName('docItemCol_VOVn');
-			Array.from(elements).forEach(element => {
-				element.classList.remove('docItemCol_VOVn');
-			});
-		}`)
+func DownloadDocusaurus(ctx context.Context, mainURL string, outputDir string, mode string, opts ...DocOption) (*RunSummary, error) {
+	return DownloadWithAdapter(ctx, &DocusaurusAdapter{}, mainURL, outputDir, mode, opts...)
+}
 
-		// 添加 pre code 样式
-		page.MustEval(`() => {
-			const style = document.createElement('style');
-			style.textContent = 'pre code { white-space: pre-wrap; overflow-wrap: anywhere; }';
-			document.head.appendChild(style);
-		}`)
+func init() {
+	RegisterAdapter("docusaurus", func() SiteAdapter {
+		return &DocusaurusAdapter{}
+	})
+}
 
-		// 删除class 元素 petercat-lui-assistant
-		page.MustEval(`() => {
-			var elementToRemove = document.querySelector('.petercat-lui-assistant');
-			if (elementToRemove) {
-				var parentElement = elementToRemove.parentNode;
-				parentElement.removeChild(elementToRemove);
-			}
+// DocusaurusAdapter docusaurus适配器
+type DocusaurusAdapter struct {
+	BaseAdapter
+}
 
-			// 移除评论 id comments
-			var element = document.getElementById("comments");
-			if (element) {
-				element.parentNode.removeChild(element);
-			}
-		}`)
+// Name description
+func (a *DocusaurusAdapter) Name() string {
+	return "docusaurus"
+}
 
-		// 平滑滚动到底部，确保所有内容加载
-		page.MustEval(`() => {
-			return new Promise((resolve) => {
-				const totalHeight = document.documentElement.scrollHeight;
-				let currentPosition = 0;
-				const step = 300; // 每次滚动300像素
-				const delay = 300; // 每次滚动间隔ms
-				
-				const smoothScroll = () => {
-					if (currentPosition < totalHeight) {
-						currentPosition = Math.min(currentPosition + step, totalHeight);
-						window.scrollTo(0, currentPosition);
-						setTimeout(smoothScroll, delay);
-					} else {
-						resolve();
-					}
-				};
-				
-				smoothScroll();
-			});
-		}`)
+// Brief description
+func (a *DocusaurusAdapter) Brief() string {
+	return "docusaurus文档转换为pdf或markdown"
+}
 
-		// 等待图片渲染完成
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
+// MenuRootSelector description
+func (a *DocusaurusAdapter) MenuRootSelector() string {
+	return "ul.theme-doc-sidebar-menu.menu__list"
+}
 
-		err := utils.Retry(ctx,
-			utils.BackoffSleeper(
-				100*time.Millisecond, // 初始等待
-				2*time.Second,        // 最大等待5s
-				nil,                  // 使用默认退避算法
-			),
-			func() (stop bool, err error) {
-				res, err := page.Eval(`() => {
-					const images = document.querySelectorAll('img');
-					for (let i = 0; i < images.length; i++) {
-						if (!images[i].complete || images[i].naturalWidth === 0) {
-							return false;
-						}
-					}
-					return true;
-            	}`)
-				if err != nil {
-					return true, err
-				}
-				allLoaded := res.Value.Bool()
-				if allLoaded {
-					return true, nil
-				}
-				log.Println("图片未加载完成，继续等待...")
-				return false, nil // 继续重试
-			})
+// ParseMenu description
+func (a *DocusaurusAdapter) ParseMenu(doc *DocDownload, root *rod.Element, level int, dirPath string) ([]*DocNode, error) {
+	return ParseDocusaurusMenu(doc, root, level, dirPath), nil
+}
 
-		if err != nil {
-			log.Printf("等待图片渲染完成时出错: %v\n", err)
-		} else {
-			log.Println("图片已渲染完成")
-		}
+// MarkdownOptions description
+func (a *DocusaurusAdapter) MarkdownOptions() *MarkdownOptions {
+	return &MarkdownOptions{
+		ContentSelector: "article",
+		Strip: []string{
+			"nav.theme-doc-breadcrumbs",
+			"div.theme-doc-toc-mobile",
+			"span.theme-doc-version-badge",
+			"footer.theme-doc-footer",
+			"a.hash-link",
+			"button",
+		},
 	}
-	doc.MenuRootSelector = "ul.theme-doc-sidebar-menu.menu__list"
-	doc.ParseMenu = ParseDocusaurusMenu
-	doc.Start()
+}
+
+// PrintOptions description
+func (a *DocusaurusAdapter) PrintOptions() *PrintOptions {
+	return &PrintOptions{PaperWidth: 20, PageHeight: 11, SinglePage: true}
+}
 
+// Finish description
+func (a *DocusaurusAdapter) Finish(doc *DocDownload) error {
 	if doc.Mode == DocDownloadModePDF {
 		// 复制文件到其它目录
 		log.Println(doc.Move("./dist"))
 	}
-	// 复制文件到其它目录
-	// log.Println(doc.Move("./dist"))
+	return nil
+}
+
+// PreparePage 保存pdf前可自定义操作
+//
+// createTime: 2026-10-18 09:05:00
+func (a *DocusaurusAdapter) PreparePage(doc *DocDownload, page *rod.Page) error {
+	// #__docusaurus_skipToContent_fallback > div > div > main > div > div > div.col.docItemCol_VOVn
+	// 删除指定class的样式
+	if _, err := page.Eval(`() => {
+		const elements = document.getElementsByClassName('docItemCol_VOVn');
+		Array.from(elements).forEach(element => {
+			element.classList.remove('docItemCol_VOVn');
+		});
+	}`); err != nil {
+		return err
+	}
+
+	// 添加 pre code 样式
+	if _, err := page.Eval(`() => {
+		const style = document.createElement('style');
+		style.textContent = 'pre code { white-space: pre-wrap; overflow-wrap: anywhere; }';
+		document.head.appendChild(style);
+	}`); err != nil {
+		return err
+	}
+
+	// 删除class 元素 petercat-lui-assistant
+	if _, err := page.Eval(`() => {
+		var elementToRemove = document.querySelector('.petercat-lui-assistant');
+		if (elementToRemove) {
+			var parentElement = elementToRemove.parentNode;
+			parentElement.removeChild(elementToRemove);
+		}
+
+		// 移除评论 id comments
+		var element = document.getElementById("comments");
+		if (element) {
+			element.parentNode.removeChild(element);
+		}
+	}`); err != nil {
+		return err
+	}
+
+	// 平滑滚动到底部，确保所有内容加载
+	if err := ScrollToBottom(page); err != nil {
+		return err
+	}
+	// 等待图片渲染完成
+	WaitImages(page, 30*time.Second)
+	return nil
 }
 
 // ParseDocusaurusMenu 解析菜单
@@ -200,11 +190,15 @@ func DownloadDocusaurus(mainURL string, outputDir string) {
 // createTime: 2023-07-11 16:13:27
 //
 // author: hailaz
-func ParseDocusaurusMenu(doc *DocDownload, root *rod.Element, level int, dirPath string, bms *[]pdfcpu.Bookmark) {
+func ParseDocusaurusMenu(doc *DocDownload, root *rod.Element, level int, dirPath string) []*DocNode {
 	log.Printf("开始解析菜单级别 %d, 路径: %s", level, dirPath)
+	nodes := make([]*DocNode, 0)
 	index := 0
 	// 循环当前节点的li
 	for li, err := root.Element("li"); err == nil; li, err = li.Next() {
+		if doc.Context().Err() != nil {
+			return nodes
+		}
 		log.Printf("处理第 %d 个菜单项", index+1)
 
 		// 获取当前节点的a标签
@@ -214,7 +208,9 @@ func ParseDocusaurusMenu(doc *DocDownload, root *rod.Element, level int, dirPath
 			continue
 		}
 
-		log.Printf("获取a标签成功: %s", a.MustHTML())
+		if aHTML, err := a.HTML(); err == nil {
+			log.Printf("获取a标签成功: %s", aHTML)
+		}
 
 		// 获取a标签的href属性
 		href, err := a.Attribute("href")
@@ -231,29 +227,16 @@ func ParseDocusaurusMenu(doc *DocDownload, root *rod.Element, level int, dirPath
 		}
 		log.Printf("正在处理菜单项: [%s] href=%s", text, *href)
 
-		*bms = append(*bms, pdfcpu.Bookmark{
-			Title:    text,
-			PageFrom: doc.pageFrom,
-		})
-		// 判断是否是链接
-		if *href != "#" {
-			url := doc.baseURL + *href
-			log.Printf("准备下载页面: %s", url)
-
-			fileName := fmt.Sprintf("%d-%s.pdf", index, text)
-			fullPath := path.Join(dirPath, fileName)
-			log.Printf("保存PDF文件: %s", fullPath)
-
-			doc.fileList = append(doc.fileList, fullPath)
-			doc.SavePDF(fullPath, url)
-
-			page, err := api.PageCountFile(fullPath)
-			if err != nil {
-				log.Printf("[错误] 获取PDF页数失败: %s", err)
-			}
-			doc.pageFrom = doc.pageFrom + page
-			log.Printf("文档累计页数%d，当前文件页数%d： %s", doc.pageFrom, page, fullPath)
+		node := &DocNode{
+			Title: text,
+		}
+		// 判断是否是链接，使用解析后的完整地址，hash路由的站点也能正确拼接。
+		// 输出路径由文档树按菜单顺序生成，标题中的非法字符会被去掉
+		if pageURL := sameSiteURL(doc, a); *href != "#" && pageURL != "" {
+			node.URL = pageURL
+			log.Printf("发现页面: %s", node.URL)
 		}
+		nodes = append(nodes, node)
 
 		// 一级菜单 theme-doc-sidebar-item-link theme-doc-sidebar-item-link-level-1 menu__list-item
 		// 一级菜单（目录） theme-doc-sidebar-item-category theme-doc-sidebar-item-category-level-1 menu__list-item
@@ -282,9 +265,8 @@ func ParseDocusaurusMenu(doc *DocDownload, root *rod.Element, level int, dirPath
 
 				if ul, err := li.Element("ul"); err == nil {
 					log.Printf("开始处理子菜单: %s", text)
-					dirName := fmt.Sprintf("%d-%s", index, text)
-					(*bms)[index].Kids = make([]pdfcpu.Bookmark, 0)
-					doc.ParseMenu(doc, ul, level+1, path.Join(dirPath, dirName), &((*bms)[index].Kids))
+					dirName := fmt.Sprintf("%d-%s", index, validFileName.ReplaceAllString(text, ""))
+					node.Children = ParseDocusaurusMenu(doc, ul, level+1, path.Join(dirPath, dirName))
 				} else {
 					log.Printf("[错误] 获取子菜单ul元素失败: %s", err)
 				}
@@ -295,4 +277,5 @@ func ParseDocusaurusMenu(doc *DocDownload, root *rod.Element, level int, dirPath
 		log.Printf("完成处理第 %d 个菜单项", index)
 	}
 	log.Printf("完成菜单级别 %d 的解析，共处理 %d 个项目", level, index)
+	return nodes
 }
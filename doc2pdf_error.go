@@ -0,0 +1,114 @@
+package doc2pdf
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const (
+	// StageInit 初始化任务，入口地址错误或适配器初始化失败
+	StageInit = "init"
+	// StageBrowser 启动浏览器
+	StageBrowser = "browser"
+	// StageAuth 登录
+	StageAuth = "auth"
+	// StageMenu 解析菜单
+	StageMenu = "menu"
+	// StagePage 打开页面
+	StagePage = "page"
+	// StagePDF 保存pdf
+	StagePDF = "pdf"
+	// StageMarkdown 保存markdown
+	StageMarkdown = "markdown"
+	// StageMerge 合并pdf
+	StageMerge = "merge"
+	// StageBookmark 添加书签
+	StageBookmark = "bookmark"
+	// StageSplit 切分pdf
+	StageSplit = "split"
+	// StageFinish 适配器结束处理
+	StageFinish = "finish"
+)
+
+// PageError 下载过程中的错误，记录失败的页面和阶段
+type PageError struct {
+	Stage string // 失败阶段
+	URL   string // 页面地址
+	File  string // 输出文件
+	Err   error  // 原始错误
+}
+
+// Error description
+func (e *PageError) Error() string {
+	var b strings.Builder
+	b.WriteString("[" + e.Stage + "]")
+	if e.URL != "" {
+		b.WriteString(" " + e.URL)
+	}
+	if e.File != "" {
+		b.WriteString(" (" + e.File + ")")
+	}
+	b.WriteString(": ")
+	if e.Err != nil {
+		b.WriteString(e.Err.Error())
+	}
+	return b.String()
+}
+
+// Unwrap description
+func (e *PageError) Unwrap() error {
+	return e.Err
+}
+
+// NewPageError 创建页面错误
+//
+// createTime: 2026-10-18 09:07:09
+func NewPageError(stage string, pageURL string, file string, err error) *PageError {
+	return &PageError{Stage: stage, URL: pageURL, File: file, Err: err}
+}
+
+// RunSummary 下载结果汇总
+type RunSummary struct {
+	mu        sync.Mutex
+	Total     int          // 处理的页面数
+	Succeeded int          // 成功的页面数
+	Failed    []*PageError // 失败的页面
+}
+
+// addSuccess description
+func (s *RunSummary) addSuccess() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Total++
+	s.Succeeded++
+}
+
+// addFailure description
+func (s *RunSummary) addFailure(err *PageError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Total++
+	s.Failed = append(s.Failed, err)
+}
+
+// HasFailed 是否有失败的页面
+//
+// createTime: 2026-10-18 09:07:09
+func (s *RunSummary) HasFailed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.Failed) > 0
+}
+
+// String description
+func (s *RunSummary) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var b strings.Builder
+	fmt.Fprintf(&b, "共%d页，成功%d页，失败%d页", s.Total, s.Succeeded, len(s.Failed))
+	for _, err := range s.Failed {
+		b.WriteString("\n  " + err.Error())
+	}
+	return b.String()
+}
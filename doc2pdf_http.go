@@ -0,0 +1,57 @@
+package doc2pdf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// HTTPClient 不经过浏览器直接请求页面时使用的客户端，配置登录后携带浏览器的cookie和http头
+//
+// createTime: 2026-10-18 09:24:41
+func (doc *DocDownload) HTTPClient() *http.Client {
+	if doc.httpClient != nil {
+		return doc.httpClient
+	}
+	return http.DefaultClient
+}
+
+// FetchDocument 不经过浏览器直接下载页面并解析，用于静态页面的快速发现
+//
+// createTime: 2026-10-18 09:24:41
+func (doc *DocDownload) FetchDocument(pageURL string) (*goquery.Document, error) {
+	data, err := doc.FetchBytes(pageURL)
+	if err != nil {
+		return nil, err
+	}
+	return goquery.NewDocumentFromReader(bytes.NewReader(data))
+}
+
+// FetchBytes 不经过浏览器直接下载文件，受PageTimeout控制
+//
+// createTime: 2026-10-18 09:36:25
+func (doc *DocDownload) FetchBytes(fileURL string) ([]byte, error) {
+	ctx := doc.Context()
+	if doc.PageTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, doc.PageTimeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := doc.HTTPClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("响应状态错误: %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
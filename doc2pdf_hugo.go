@@ -0,0 +1,233 @@
+package doc2pdf
+
+import (
+	"context"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/go-rod/rod"
+)
+
+// DownloadHugo 下载hugo主题(Docsy、Hextra、Book)的文档，mode为pdf或md
+//
+// createTime: 2026-10-18 09:34:49
+func DownloadHugo(ctx context.Context, mainURL string, outputDir string, mode string, opts ...DocOption) (*RunSummary, error) {
+	return DownloadWithAdapter(ctx, &HugoAdapter{}, mainURL, outputDir, mode, opts...)
+}
+
+func init() {
+	RegisterAdapter("hugo", func() SiteAdapter {
+		return &HugoAdapter{}
+	})
+}
+
+// HugoAdapter hugo文档主题适配器，支持Docsy、Hextra和Book主题，侧边栏的章节层级保留为书签层级。
+// Docsy开启sidebar_menu_compact时侧边栏只包含当前章节，只能导出当前章节
+type HugoAdapter struct {
+	BaseAdapter
+	Print    bool   // pdf模式下使用Docsy的章节打印页(_print)渲染
+	printURL string // 章节打印页地址
+}
+
+// Name description
+func (a *HugoAdapter) Name() string {
+	return "hugo"
+}
+
+// Brief description
+func (a *HugoAdapter) Brief() string {
+	return "hugo文档(Docsy、Hextra、Book主题)转换为pdf或markdown"
+}
+
+// Options description
+func (a *HugoAdapter) Options() []AdapterOption {
+	return []AdapterOption{
+		{Name: "print", Brief: "使用Docsy的章节打印页(_print)渲染，入口地址应为章节首页", Orphan: true},
+	}
+}
+
+// SetOption description
+func (a *HugoAdapter) SetOption(name string, value string) error {
+	if name == "print" {
+		var err error
+		a.Print, err = ParseBoolOption(name, value)
+		return err
+	}
+	return a.BaseAdapter.SetOption(name, value)
+}
+
+// MenuRootSelector Docsy、Hextra和Book的侧边栏
+func (a *HugoAdapter) MenuRootSelector() string {
+	return "#td-sidebar-menu nav.td-sidebar-nav, aside.hextra-sidebar-container, aside.book-menu nav"
+}
+
+// ParseMenu description
+func (a *HugoAdapter) ParseMenu(doc *DocDownload, root *rod.Element, level int, dirPath string) ([]*DocNode, error) {
+	if a.Print {
+		a.printURL = hugoPrintURL(doc, root.Page())
+		log.Println("章节打印页", a.printURL)
+	}
+	menu, err := menuSelection(root)
+	if err != nil {
+		return nil, err
+	}
+	return doc.menuNodes(ParseHugoMenu(menu), make(map[string]bool)), nil
+}
+
+// MarkdownOptions description
+func (a *HugoAdapter) MarkdownOptions() *MarkdownOptions {
+	return &MarkdownOptions{
+		ContentSelector: "main div.td-content, article.book-article, main article",
+		Strip: []string{
+			"a.td-heading-self-link",
+			"div.td-page-meta__lastmod",
+			"div.d-print-none",
+			"a.book-anchor",
+			"a.subheading-anchor",
+			"div.hextra-code-copy-btn-container",
+		},
+	}
+}
+
+// PrintOptions description
+func (a *HugoAdapter) PrintOptions() *PrintOptions {
+	return &PrintOptions{PaperWidth: 15, PageHeight: 11, SinglePage: true}
+}
+
+// PreparePage 删除导航栏、侧边栏、页内目录、反馈和页脚，正文铺满页面
+//
+// createTime: 2026-10-18 09:34:49
+func (a *HugoAdapter) PreparePage(doc *DocDownload, page *rod.Page) error {
+	if err := RemoveElements(page,
+		// Docsy
+		"header nav.td-navbar",
+		"aside.td-sidebar",
+		"aside.td-sidebar-toc",
+		"nav.td-breadcrumbs",
+		"div.td-page-meta",
+		"div.d-print-none",
+		"footer.td-footer",
+		// Hextra
+		"div.hextra-nav-container",
+		"aside.hextra-sidebar-container",
+		"nav.hextra-toc",
+		"footer.hextra-footer",
+		// Book
+		"aside.book-menu",
+		"aside.book-toc",
+		"header.book-header",
+		"footer.book-footer",
+		"label.book-menu-overlay",
+	); err != nil {
+		return err
+	}
+	if err := AddStyle(page, `
+		.td-main main, .td-main main[role=main] { flex: 0 0 100% !important; max-width: 100% !important; padding: 0 1rem !important; }
+		.td-outer, .td-main { padding-top: 0 !important; }
+		.book-page { min-width: 0 !important; max-width: none !important; }
+		article { max-width: none !important; }
+		pre, pre code { white-space: pre-wrap !important; overflow-wrap: anywhere; }
+	`); err != nil {
+		return err
+	}
+	WaitImages(page, 30*time.Second)
+	return nil
+}
+
+// Exporter 开启print时使用章节打印页逐章渲染，恢复和增量导出需要逐页校验，不使用打印页
+func (a *HugoAdapter) Exporter(doc *DocDownload) Exporter {
+	if a.printURL == "" || doc.Mode != DocDownloadModePDF || doc.Resume || doc.Incremental {
+		return nil
+	}
+	return &PrintViewExporter{
+		URL:     a.printURL,
+		Split:   hugoSplitChapters,
+		Prepare: a.PreparePage,
+		Print:   a.PrintOptions(),
+	}
+}
+
+// hugoSplitChapters Docsy打印页中每个页面是一个分页的div.td-content，
+// 打印页开头的章节目录不是页面，直接删除
+const hugoSplitChapters = `() => {
+	const blocks = Array.from(document.querySelectorAll('main div.td-content'))
+		.filter(el => !el.parentElement.closest('div.td-content'));
+	const chapters = blocks.filter(el => (el.getAttribute('style') || '').includes('page-break-before'));
+	if (chapters.length === 0) {
+		blocks.forEach(el => el.classList.add('doc2pdf-chapter'));
+		return blocks.length;
+	}
+	blocks.filter(el => !chapters.includes(el)).forEach(el => el.remove());
+	chapters.forEach(el => {
+		el.classList.add('doc2pdf-chapter');
+		el.style.pageBreakBefore = 'auto';
+	});
+	return chapters.length;
+}`
+
+// hugoPrintURL 优先使用页面中"打印整个章节"的链接，没有时在入口地址后加_print/
+//
+// createTime: 2026-10-18 09:34:49
+func hugoPrintURL(doc *DocDownload, page *rod.Page) string {
+	if has, link, err := page.Has(`a[href$="/_print/"]`); err == nil && has {
+		if printURL := sameSiteURL(doc, link); printURL != "" {
+			return printURL
+		}
+	}
+	u, err := url.Parse(doc.MainURL)
+	if err != nil {
+		return ""
+	}
+	u.Fragment = ""
+	u.RawQuery = ""
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	u.Path += "_print/"
+	return u.String()
+}
+
+// ParseHugoMenu 解析嵌套列表形式的侧边栏，li中不属于子列表的第一个链接作为页面，
+// 没有链接的分组使用label或span的文本作为标题
+//
+// createTime: 2026-10-18 09:34:49
+func ParseHugoMenu(root *goquery.Selection) []MenuItem {
+	var parse func(ul *goquery.Selection) []MenuItem
+	parse = func(ul *goquery.Selection) []MenuItem {
+		items := make([]MenuItem, 0)
+		ul.ChildrenFiltered("li").Each(func(_ int, li *goquery.Selection) {
+			item := MenuItem{}
+			link := ownElement(li, "a")
+			label := link
+			if label.Length() == 0 {
+				label = ownElement(li, "label, span")
+			}
+			item.Title = menuText(label)
+			item.Href = menuHref(link)
+			if sub := ownElement(li, "ul"); sub.Length() > 0 {
+				item.Children = parse(sub)
+			}
+			items = append(items, item)
+		})
+		return items
+	}
+	items := make([]MenuItem, 0)
+	root.Find("ul").Each(func(_ int, ul *goquery.Selection) {
+		if ul.ParentsFiltered("li").Length() == 0 {
+			items = append(items, parse(ul)...)
+		}
+	})
+	return items
+}
+
+// ownElement li中第一个不属于子列表的匹配元素
+//
+// createTime: 2026-10-18 09:34:49
+func ownElement(li *goquery.Selection, selector string) *goquery.Selection {
+	return li.Find(selector).FilterFunction(func(_ int, s *goquery.Selection) bool {
+		return s.Parent().Closest("li").IsSelection(li)
+	}).First()
+}
@@ -0,0 +1,167 @@
+package doc2pdf_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hailaz/doc2pdf"
+)
+
+const docsySidebar = `<aside class="td-sidebar"><div id="td-sidebar-menu" class="td-sidebar__inner">
+	<nav class="td-sidebar-nav"><ul class="td-sidebar-nav__section pe-md-3 ul-0">
+		<li class="td-sidebar-nav__section-title td-sidebar-nav__section with-child">
+			<a href="/docs/" class="align-left td-sidebar-link td-sidebar-link__section tree-root"><span>Docs</span></a>
+			<ul class="ul-1">
+				<li class="td-sidebar-nav__section-title"><a href="/docs/start/" class="td-sidebar-link"><span>Getting Started</span></a></li>
+				<li class="td-sidebar-nav__section-title with-child"><a href="/docs/tasks/" class="td-sidebar-link"><span>Tasks</span></a>
+					<ul class="ul-2"><li><a href="/docs/tasks/build/" class="td-sidebar-link"><span>Build</span></a></li></ul>
+				</li>
+			</ul>
+		</li>
+	</ul></nav>
+</div></aside>`
+
+// docsyPage 生成带侧边栏的Docsy页面
+func docsyPage(content string) string {
+	return `<html><body><div class="td-main"><div class="row">` + docsySidebar +
+		`<main role="main">` + content + `</main></div></div></body></html>`
+}
+
+// hugoSidebars Docsy、Hextra和Book主题的侧边栏
+var hugoSidebars = []struct {
+	name    string
+	sidebar string
+	want    string
+}{
+	{
+		name:    "docsy",
+		sidebar: docsySidebar,
+		want:    "Docs[Getting Started Tasks[Build]]",
+	},
+	{
+		name: "hextra",
+		sidebar: `<aside class="hextra-sidebar-container"><div class="hextra-scrollbar"><ul class="hx-flex">
+			<li class="open"><a href="/docs/guide/">Guide</a>
+				<div class="hx-ltr"><ul><li><a href="/docs/guide/config/">Config</a></li></ul></div>
+			</li>
+			<li><a href="/docs/faq/">FAQ</a></li>
+		</ul></div></aside>`,
+		want: "Guide[Config] FAQ",
+	},
+	{
+		name: "book",
+		sidebar: `<aside class="book-menu"><div class="book-menu-content"><nav><ul>
+			<li><input type="checkbox" id="section-1" class="toggle"><label for="section-1"><span>Section</span></label>
+				<ul><li><a href="/docs/section/first/">First</a></li></ul>
+			</li>
+			<li><a href="/docs/about/">About</a></li>
+		</ul></nav></div></aside>`,
+		want: "Section[First] About",
+	},
+}
+
+// TestParseHugoMenu 解析Docsy、Hextra和Book主题的侧边栏，子列表中的链接不作为分组标题
+//
+// createTime: 2026-10-18 09:34:49
+func TestParseHugoMenu(t *testing.T) {
+	selector := (&doc2pdf.HugoAdapter{}).MenuRootSelector()
+	for _, c := range hugoSidebars {
+		t.Run(c.name, func(t *testing.T) {
+			items := doc2pdf.ParseHugoMenu(menuFixture(t, c.sidebar, selector))
+			if got := menuTitles(items); got != c.want {
+				t.Errorf("menu = %s", got)
+			}
+		})
+	}
+}
+
+// TestHugoMenu 在浏览器中解析侧边栏，未设置print时逐页渲染
+//
+// createTime: 2026-10-18 09:34:49
+func TestHugoMenu(t *testing.T) {
+	for _, c := range hugoSidebars {
+		t.Run(c.name, func(t *testing.T) {
+			pages := map[string]string{"/docs/": `<html><body>` + c.sidebar + `<main>doc</main></body></html>`}
+			doc := newFixtureDoc(t, &doc2pdf.HugoAdapter{}, pages, "/docs/")
+			tree, err := doc.Discover(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := treeTitles(tree.Nodes); got != c.want {
+				t.Errorf("tree = %s", got)
+			}
+			if _, ok := doc.Exporter().(*doc2pdf.PDFExporter); !ok {
+				t.Error("print view should be used only with the print option")
+			}
+		})
+	}
+}
+
+// TestHugoPrintURL 打印页地址优先使用页面中的链接，没有时在入口地址后加_print/
+//
+// createTime: 2026-10-18 09:34:49
+func TestHugoPrintURL(t *testing.T) {
+	cases := []struct {
+		name  string
+		index string
+		link  string
+		want  string
+	}{
+		{name: "derived", index: "/docs?lang=en#top", want: "/docs/_print/"},
+		{name: "link", index: "/docs/", link: `<a href="/docs/all/_print/" class="td-page-meta--print">Print entire section</a>`, want: "/docs/all/_print/"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			adapter := &doc2pdf.HugoAdapter{}
+			if err := adapter.SetOption("print", "true"); err != nil {
+				t.Fatal(err)
+			}
+			page := docsyPage(c.link)
+			doc := newFixtureDoc(t, adapter, map[string]string{"/docs": page, "/docs/": page}, c.index)
+			if _, err := doc.Discover(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			exporter, ok := doc.Exporter().(*doc2pdf.PrintViewExporter)
+			if !ok || exporter.URL != strings.TrimSuffix(doc.MainURL, c.index)+c.want {
+				t.Errorf("exporter = %#v", doc.Exporter())
+			}
+		})
+	}
+}
+
+// TestHugoPrintView Docsy打印页中分页的div.td-content逐个打印到对应页面，开头的章节目录不是页面
+//
+// createTime: 2026-10-18 09:34:49
+func TestHugoPrintView(t *testing.T) {
+	adapter := &doc2pdf.HugoAdapter{}
+	if err := adapter.SetOption("print", "true"); err != nil {
+		t.Fatal(err)
+	}
+	chapter := func(title string) string {
+		return `<div class="td-content" style="page-break-before: always"><h1>` + title + `</h1></div>`
+	}
+	pages := map[string]string{
+		"/docs/":             docsyPage(`<div class="td-content"><h1>Docs</h1><div class="td-content">section toc</div></div>`),
+		"/docs/start/":       docsyPage(`<h1>Getting Started</h1>`),
+		"/docs/tasks/":       docsyPage(`<h1>Tasks</h1>`),
+		"/docs/tasks/build/": docsyPage(`<h1>Build</h1>`),
+		"/docs/_print/": docsyPage(`<div class="td-content"><h1>Docs</h1><div class="td-content">section toc</div></div>` +
+			chapter("Docs") + chapter("Getting Started") + chapter("Tasks") + chapter("Build")),
+	}
+	doc, site := newFixtureSite(t, adapter, pages, "/docs/")
+	tree, err := doc.Discover(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	menuRequests := site.Requests("/docs/")
+	exportFixture(t, doc, tree)
+	if site.Requests("/docs/_print/") != 1 || site.Requests("/docs/") != menuRequests {
+		t.Errorf("print = %d, index = %d", site.Requests("/docs/_print/"), site.Requests("/docs/"))
+	}
+	for _, p := range []string{"/docs/start/", "/docs/tasks/", "/docs/tasks/build/"} {
+		if site.Requests(p) != 0 {
+			t.Errorf("%s should be rendered from the print page", p)
+		}
+	}
+}
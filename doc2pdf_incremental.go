@@ -0,0 +1,264 @@
+package doc2pdf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"sync"
+
+	"github.com/go-rod/rod"
+	"github.com/gogf/gf/v2/crypto/gmd5"
+	"github.com/gogf/gf/v2/os/gfile"
+)
+
+// ChangeReport 增量导出的变更报告
+type ChangeReport struct {
+	mu        sync.Mutex
+	Added     []*ManifestPage // 新增页面
+	Modified  []*ManifestPage // 内容变化的页面
+	Moved     []*ManifestPage // 内容未变但位置变化的页面
+	Removed   []*ManifestPage // 已删除的页面
+	Unchanged []*ManifestPage // 未变化的页面
+}
+
+// add description
+func (r *ChangeReport) add(list *[]*ManifestPage, node *DocNode) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	*list = append(*list, &ManifestPage{Title: node.Title, URL: node.URL, File: node.Path})
+}
+
+// String description
+//
+// createTime: 2026-10-18 09:18:43
+func (r *ChangeReport) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return fmt.Sprintf("新增%d页，修改%d页，移动%d页，删除%d页，未变化%d页",
+		len(r.Added), len(r.Modified), len(r.Moved), len(r.Removed), len(r.Unchanged))
+}
+
+// Markdown 生成markdown格式的变更报告
+//
+// createTime: 2026-10-18 09:18:43
+func (r *ChangeReport) Markdown() string {
+	var buf bytes.Buffer
+	buf.WriteString("# 变更报告\n\n")
+	buf.WriteString(r.String() + "\n")
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sections := []struct {
+		title string
+		pages []*ManifestPage
+	}{
+		{"新增", r.Added},
+		{"修改", r.Modified},
+		{"移动", r.Moved},
+		{"删除", r.Removed},
+	}
+	for _, section := range sections {
+		if len(section.pages) == 0 {
+			continue
+		}
+		fmt.Fprintf(&buf, "\n## %s\n\n", section.title)
+		for _, p := range section.pages {
+			fmt.Fprintf(&buf, "- [%s](%s) `%s`\n", p.Title, p.URL, p.File)
+		}
+	}
+	return buf.String()
+}
+
+// WithIncremental 增量导出，只重新渲染指纹变化或新增的页面
+//
+// createTime: 2026-10-18 09:18:43
+func WithIncremental(incremental bool) DocOption {
+	return func(doc *DocDownload) {
+		doc.Incremental = incremental
+	}
+}
+
+// Changes 返回增量导出的变更报告，非增量导出时为nil
+//
+// createTime: 2026-10-18 09:18:43
+func (doc *DocDownload) Changes() *ChangeReport {
+	return doc.changes
+}
+
+// ChangesFile 变更报告文件，位于输出目录旁
+//
+// createTime: 2026-10-18 09:18:43
+func (doc *DocDownload) ChangesFile() string {
+	return doc.OutputDir() + ".changes.md"
+}
+
+// stageDir 增量导出时暂存位置变化文件的目录
+func (doc *DocDownload) stageDir() string {
+	return doc.OutputDir() + "-incremental"
+}
+
+// stagedFile 页面在暂存目录中的文件
+func (doc *DocDownload) stagedFile(pageURL string) string {
+	name, _ := gmd5.EncryptString(pageURL)
+	return path.Join(doc.stageDir(), name+doc.FileExt())
+}
+
+// prepareIncremental 对比上次的清单，删除已移除页面的文件，并将位置变化的文件移到暂存目录，
+// 避免并发渲染时新旧文件互相覆盖
+//
+// createTime: 2026-10-18 09:18:43
+func (doc *DocDownload) prepareIncremental(prev *Manifest, tree *DocTree) {
+	doc.changes = &ChangeReport{}
+	doc.prevManifest = prev
+	gfile.Remove(doc.stageDir())
+	if prev == nil {
+		return
+	}
+	nodes := make(map[string]*DocNode)
+	for _, node := range tree.Pages() {
+		nodes[node.URL] = node
+	}
+	for _, entry := range prev.Pages {
+		node, ok := nodes[entry.URL]
+		if !ok {
+			log.Println("页面已删除", entry.Title, entry.URL)
+			doc.changes.add(&doc.changes.Removed, &DocNode{Title: entry.Title, URL: entry.URL, Path: entry.File})
+			os.Remove(entry.File)
+			continue
+		}
+		if node.Path == entry.File || !gfile.Exists(entry.File) {
+			continue
+		}
+		staged := doc.stagedFile(entry.URL)
+		if err := moveFile(entry.File, staged); err != nil {
+			log.Println("[err]暂存文件失败:", err)
+			os.Remove(entry.File)
+		}
+	}
+}
+
+// reuseUnchanged 页面指纹与上次一致且文件有效时直接使用上次的文件
+//
+// createTime: 2026-10-18 09:18:43
+func (doc *DocDownload) reuseUnchanged(node *DocNode) bool {
+	fp, err := doc.Adapter.Fingerprint(doc, node.URL)
+	if err != nil {
+		log.Println("[err]获取页面指纹失败:", node.URL, err)
+	}
+	node.Fingerprint = fp
+
+	var entry *ManifestPage
+	if doc.prevManifest != nil {
+		entry = doc.prevManifest.FindURL(node.URL)
+	}
+	file := node.Path
+	staged := doc.stagedFile(node.URL)
+	if gfile.Exists(staged) {
+		file = staged
+	}
+	if entry == nil {
+		doc.changes.add(&doc.changes.Added, node)
+		os.Remove(node.Path)
+		return false
+	}
+	if entry.Status == PageStatusDone && fp != "" && fp == entry.Fingerprint && doc.prevManifest.validFile(file, entry) {
+		if file == node.Path {
+			doc.changes.add(&doc.changes.Unchanged, node)
+		} else if err := moveFile(file, node.Path); err == nil {
+			doc.changes.add(&doc.changes.Moved, node)
+		} else {
+			log.Println("[err]移动文件失败:", err)
+			doc.changes.add(&doc.changes.Modified, node)
+			os.Remove(file)
+			return false
+		}
+		node.PageCount = entry.PageCount
+		return true
+	}
+	doc.changes.add(&doc.changes.Modified, node)
+	os.Remove(file)
+	os.Remove(node.Path)
+	return false
+}
+
+// finishIncremental 清理暂存目录并保存变更报告
+//
+// createTime: 2026-10-18 09:18:43
+func (doc *DocDownload) finishIncremental() {
+	gfile.Remove(doc.stageDir())
+	log.Println("变更报告", doc.changes)
+	if err := gfile.PutContents(doc.ChangesFile(), doc.changes.Markdown()); err != nil {
+		log.Println("[err]保存变更报告失败:", err)
+	}
+}
+
+// HeaderFingerprint 使用响应头中的ETag或Last-Modified作为页面指纹，都没有时返回空
+//
+// createTime: 2026-10-18 09:18:43
+func (doc *DocDownload) HeaderFingerprint(pageURL string) (string, error) {
+	ctx := doc.Context()
+	if doc.PageTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, doc.PageTimeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, pageURL, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := doc.HTTPClient().Do(req)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("响应状态错误: %s", resp.Status)
+	}
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		return "etag:" + etag, nil
+	}
+	if lastModified := resp.Header.Get("Last-Modified"); lastModified != "" {
+		return "last-modified:" + lastModified, nil
+	}
+	return "", nil
+}
+
+// ContentFingerprint 打开页面，使用selector对应元素文本的md5作为页面指纹
+//
+// createTime: 2026-10-18 09:18:43
+func (doc *DocDownload) ContentFingerprint(pageURL string, selector string) (string, error) {
+	page, err := doc.OpenPage(pageURL)
+	if err != nil {
+		return "", err
+	}
+	defer doc.ClosePage(page)
+	return textFingerprint(page, selector)
+}
+
+// textFingerprint 元素文本的md5
+func textFingerprint(page *rod.Page, selector string) (string, error) {
+	el, err := page.Element(selector)
+	if err != nil {
+		return "", err
+	}
+	text, err := el.Text()
+	if err != nil {
+		return "", err
+	}
+	hash, err := gmd5.EncryptString(text)
+	if err != nil {
+		return "", err
+	}
+	return "md5:" + hash, nil
+}
+
+// moveFile 移动文件，目标目录不存在时自动创建
+func moveFile(src string, dst string) error {
+	if err := os.MkdirAll(path.Dir(dst), os.ModePerm); err != nil {
+		return err
+	}
+	return gfile.Move(src, dst)
+}
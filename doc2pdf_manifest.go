@@ -0,0 +1,186 @@
+package doc2pdf
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/crypto/gmd5"
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+)
+
+const (
+	// PageStatusPending 未渲染
+	PageStatusPending = "pending"
+	// PageStatusDone 渲染成功
+	PageStatusDone = "done"
+	// PageStatusFailed 渲染失败
+	PageStatusFailed = "failed"
+)
+
+// ManifestPage 单个页面的渲染记录
+type ManifestPage struct {
+	Title       string `json:"title"`                 // 页面标题
+	URL         string `json:"url"`                   // 页面地址
+	File        string `json:"file"`                  // 输出文件
+	PageCount   int    `json:"pageCount"`             // pdf页数
+	Checksum    string `json:"checksum"`              // 输出文件的md5
+	Fingerprint string `json:"fingerprint,omitempty"` // 页面内容指纹，用于增量导出
+	Status      string `json:"status"`                // 渲染状态
+	Error       string `json:"error,omitempty"`       // 失败原因
+
+	Meta map[string]string `json:"meta,omitempty"` // 页面元数据
+}
+
+// Manifest 下载清单，保存在输出目录旁，用于中断后恢复下载
+type Manifest struct {
+	MainURL   string                   `json:"mainURL"`   // 文档入口地址
+	Mode      string                   `json:"mode"`      // 下载模式
+	UpdatedAt time.Time                `json:"updatedAt"` // 最后更新时间
+	Tree      *DocTree                 `json:"tree"`      // 文档树，恢复时不再解析菜单
+	Pages     map[string]*ManifestPage `json:"pages"`     // 以输出文件为键的渲染记录
+
+	mu   sync.Mutex
+	file string
+}
+
+// NewManifest 根据文档树创建清单，所有页面为未渲染状态
+//
+// createTime: 2026-10-18 09:16:33
+func NewManifest(file string, mode string, tree *DocTree) *Manifest {
+	m := &Manifest{
+		MainURL: tree.MainURL,
+		Mode:    mode,
+		Tree:    tree,
+		Pages:   make(map[string]*ManifestPage),
+		file:    file,
+	}
+	for _, node := range tree.Pages() {
+		m.Pages[node.Path] = &ManifestPage{
+			Title:  node.Title,
+			URL:    node.URL,
+			File:   node.Path,
+			Status: PageStatusPending,
+			Meta:   node.Meta,
+		}
+	}
+	return m
+}
+
+// LoadManifest 读取清单文件
+//
+// createTime: 2026-10-18 09:16:33
+func LoadManifest(file string) (*Manifest, error) {
+	if !gfile.Exists(file) {
+		return nil, fmt.Errorf("清单文件不存在: %s", file)
+	}
+	m := &Manifest{file: file}
+	if err := json.Unmarshal(gfile.GetBytes(file), m); err != nil {
+		return nil, fmt.Errorf("清单文件格式错误: %w", err)
+	}
+	if m.Tree == nil {
+		return nil, fmt.Errorf("清单文件缺少文档树: %s", file)
+	}
+	if m.Pages == nil {
+		m.Pages = make(map[string]*ManifestPage)
+	}
+	return m, nil
+}
+
+// Save 保存清单文件，先写临时文件再重命名，避免中断时留下不完整的清单
+//
+// createTime: 2026-10-18 09:16:33
+func (m *Manifest) Save() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	tempFile := m.file + ".tmp"
+	if err := gfile.PutBytes(tempFile, data); err != nil {
+		return err
+	}
+	return os.Rename(tempFile, m.file)
+}
+
+// Record 记录页面的渲染结果，成功时计算输出文件的校验值
+//
+// createTime: 2026-10-18 09:16:33
+func (m *Manifest) Record(node *DocNode) {
+	entry := &ManifestPage{
+		Title:       node.Title,
+		URL:         node.URL,
+		File:        node.Path,
+		PageCount:   node.PageCount,
+		Fingerprint: node.Fingerprint,
+		Status:      PageStatusDone,
+		Meta:        node.Meta,
+	}
+	err := node.Err
+	if err == nil {
+		entry.Checksum, err = gmd5.EncryptFile(node.Path)
+	}
+	if err != nil {
+		entry.Status = PageStatusFailed
+		entry.Error = err.Error()
+	}
+	m.mu.Lock()
+	m.Pages[node.Path] = entry
+	m.mu.Unlock()
+}
+
+// Reuse 判断上次的输出文件是否可以直接使用，可用时将页数写回节点。
+// 不可用的文件（失败、不完整或被修改）会被删除，以便重新渲染
+//
+// createTime: 2026-10-18 09:16:33
+func (m *Manifest) Reuse(node *DocNode) bool {
+	m.mu.Lock()
+	entry, ok := m.Pages[node.Path]
+	m.mu.Unlock()
+	if ok && entry.Status == PageStatusDone && entry.URL == node.URL && m.validFile(entry.File, entry) {
+		node.PageCount = entry.PageCount
+		node.Fingerprint = entry.Fingerprint
+		return true
+	}
+	if gfile.Exists(node.Path) {
+		log.Println("删除无效文件", node.Path)
+		os.Remove(node.Path)
+	}
+	return false
+}
+
+// FindURL 按页面地址查找渲染记录，菜单调整后输出文件可能变化，增量导出按地址匹配
+//
+// createTime: 2026-10-18 09:18:43
+func (m *Manifest) FindURL(pageURL string) *ManifestPage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, entry := range m.Pages {
+		if entry.URL == pageURL {
+			return entry
+		}
+	}
+	return nil
+}
+
+// validFile 按记录校验文件，pdf文件还需能正常读取页数
+func (m *Manifest) validFile(file string, entry *ManifestPage) bool {
+	if !gfile.Exists(file) {
+		return false
+	}
+	checksum, err := gmd5.EncryptFile(file)
+	if err != nil || checksum != entry.Checksum {
+		return false
+	}
+	if m.Mode != DocDownloadModePDF {
+		return true
+	}
+	pageCount, err := api.PageCountFile(file)
+	return err == nil && pageCount == entry.PageCount
+}
@@ -0,0 +1,90 @@
+package doc2pdf_test
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/hailaz/doc2pdf"
+)
+
+// TestManifestReuse description
+//
+// createTime: 2026-10-18 09:16:33
+func TestManifestReuse(t *testing.T) {
+	dir := t.TempDir()
+	done := &doc2pdf.DocNode{Title: "安装", URL: "https://example.com/docs/install", Path: filepath.Join(dir, "0-安装.md")}
+	broken := &doc2pdf.DocNode{Title: "配置", URL: "https://example.com/docs/config", Path: filepath.Join(dir, "1-配置.md")}
+	failed := &doc2pdf.DocNode{Title: "部署", URL: "https://example.com/docs/deploy", Path: filepath.Join(dir, "2-部署.md")}
+	tree := doc2pdf.NewDocTree("https://example.com/docs")
+	tree.Nodes = []*doc2pdf.DocNode{done, broken, failed}
+
+	file := filepath.Join(dir, "output.manifest.json")
+	m := doc2pdf.NewManifest(file, doc2pdf.DocDownloadModeMD, tree)
+	for _, node := range []*doc2pdf.DocNode{done, broken} {
+		if err := gfile.PutContents(node.Path, "# "+node.Title); err != nil {
+			t.Fatal(err)
+		}
+		m.Record(node)
+	}
+	failed.Err = errors.New("timeout")
+	m.Record(failed)
+	if err := m.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 模拟中断后残留的不完整文件
+	if err := gfile.PutContents(broken.Path, "# 配"); err != nil {
+		t.Fatal(err)
+	}
+	if err := gfile.PutContents(failed.Path, "partial"); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := doc2pdf.LoadManifest(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Tree.Pages()) != 3 {
+		t.Fatalf("tree pages: want 3, got %d", len(loaded.Tree.Pages()))
+	}
+	if got := loaded.Pages[failed.Path]; got.Status != doc2pdf.PageStatusFailed || got.Error != "timeout" {
+		t.Errorf("failed entry: unexpected %+v", got)
+	}
+	if !loaded.Reuse(done) {
+		t.Error("done: want reuse")
+	}
+	for _, node := range []*doc2pdf.DocNode{broken, failed} {
+		if loaded.Reuse(node) {
+			t.Errorf("%s: want re-render", node.Title)
+		}
+		if gfile.Exists(node.Path) {
+			t.Errorf("%s: invalid file not removed", node.Title)
+		}
+	}
+}
+
+// TestChangeReportMarkdown description
+//
+// createTime: 2026-10-18 09:18:43
+func TestChangeReportMarkdown(t *testing.T) {
+	report := &doc2pdf.ChangeReport{
+		Added:     []*doc2pdf.ManifestPage{{Title: "部署", URL: "https://example.com/docs/deploy", File: "output/2-部署.pdf"}},
+		Removed:   []*doc2pdf.ManifestPage{{Title: "旧版", URL: "https://example.com/docs/old", File: "output/3-旧版.pdf"}},
+		Unchanged: []*doc2pdf.ManifestPage{{Title: "安装", URL: "https://example.com/docs/install", File: "output/0-安装.pdf"}},
+	}
+	if got := report.String(); got != "新增1页，修改0页，移动0页，删除1页，未变化1页" {
+		t.Errorf("String: got %s", got)
+	}
+	md := report.Markdown()
+	for _, want := range []string{"## 新增", "- [部署](https://example.com/docs/deploy)", "## 删除"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown: missing %q in\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "## 修改") || strings.Contains(md, "0-安装.pdf") {
+		t.Errorf("Markdown: unexpected content\n%s", md)
+	}
+}
@@ -0,0 +1,339 @@
+package doc2pdf
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	md "github.com/JohannesKaufmann/html-to-markdown"
+	"github.com/JohannesKaufmann/html-to-markdown/plugin"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gogf/gf/v2/crypto/gmd5"
+	"github.com/gogf/gf/v2/os/gfile"
+)
+
+// PageToMD 按适配器的markdown参数提取页面正文并转换为markdown，正文html会缓存在HTMLDir()
+//
+// createTime: 2023-07-28 16:45:39
+//
+// author: hailaz
+func PageToMD(doc *DocDownload, filePath string, pageUrl string) error {
+	log.Println("PageToMD", filePath)
+	return CachedMD(doc, filePath, pageUrl, func() (string, error) {
+		page, err := doc.OpenPage(pageUrl)
+		if err != nil {
+			return "", NewPageError(StagePage, pageUrl, filePath, err)
+		}
+		defer doc.ClosePage(page)
+		opt := doc.Adapter.MarkdownOptions()
+		// 正文可能由脚本渲染，在PageTimeout内等待正文出现后再提取
+		if _, err := page.Element(opt.ContentSelector); err != nil {
+			return "", NewPageError(StagePage, pageUrl, filePath, fmt.Errorf("等待正文失败 %s: %w", opt.ContentSelector, err))
+		}
+		html, err := page.HTML()
+		if err != nil {
+			return "", NewPageError(StagePage, pageUrl, filePath, err)
+		}
+		return ContentHTML(doc, html, pageUrl, opt, page.GetResource)
+	})
+}
+
+// CachedMD 将load返回的正文html转换为markdown，正文html缓存在HTMLDir()，有缓存时不再调用load
+//
+// createTime: 2026-10-18 09:39:30
+func CachedMD(doc *DocDownload, filePath string, pageUrl string, load func() (string, error)) error {
+	cacheHtml := strings.ReplaceAll(filePath, doc.OutputDir(), doc.HTMLDir())
+	cacheHtml = strings.TrimSuffix(cacheHtml, ".md") + ".html"
+	html := ""
+
+	if _, err := os.Stat(cacheHtml); os.IsNotExist(err) {
+		// 加个缓存，免得每次都下载
+		if html, err = load(); err != nil {
+			if _, ok := err.(*PageError); ok {
+				return err
+			}
+			return NewPageError(StageMarkdown, pageUrl, filePath, err)
+		}
+		if err := gfile.PutContents(cacheHtml, html); err != nil {
+			return NewPageError(StageMarkdown, pageUrl, filePath, err)
+		}
+	} else {
+		html = gfile.GetContents(cacheHtml)
+	}
+
+	converter := NewMarkdownConverter()
+	markdown, err := converter.ConvertString(html)
+	if err != nil {
+		return NewPageError(StageMarkdown, pageUrl, filePath, err)
+	}
+	if err := gfile.PutContents(filePath, markdown); err != nil {
+		return NewPageError(StageMarkdown, pageUrl, filePath, err)
+	}
+	return nil
+}
+
+// ContentHTML 按markdown参数从页面html中提取正文，相对地址的图片通过fetch下载到StaticDir()并替换地址
+//
+// createTime: 2026-10-18 09:39:30
+func ContentHTML(doc *DocDownload, html string, pageUrl string, opt *MarkdownOptions, fetch func(resURL string) ([]byte, error)) (string, error) {
+	queryDoc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return "", err
+	}
+	for _, selector := range opt.Strip {
+		queryDoc.Find(selector).Remove()
+	}
+	content := queryDoc.Find(opt.ContentSelector).First()
+	if content.Length() == 0 {
+		return "", fmt.Errorf("正文不存在: %s", opt.ContentSelector)
+	}
+	base, err := url.Parse(pageUrl)
+	if err != nil {
+		return "", err
+	}
+	// pageDir := path.Dir(filePath)
+	pageDir := path.Join(doc.StaticDir())
+
+	content.Find("img").Each(func(i int, s *goquery.Selection) {
+		src, _ := s.Attr("src")
+		// log.Println("img src:", src)
+		if src == "" || strings.HasPrefix(src, "https://") || strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "data:") {
+			return
+		}
+		ref, err := url.Parse(src)
+		if err != nil {
+			return
+		}
+		resolved := base.ResolveReference(ref)
+		resURL := resolved.String()
+		// 保存资源文件
+		res, err := fetch(resURL)
+		if err != nil {
+			// 资源获取失败时保留原始地址
+			log.Printf("[err]GetResource %s: %s", resURL, err)
+			s.SetAttr("src", resURL)
+			return
+		}
+		// 使用新的文件名，避免无法识别
+		resBaseName := resolved.Path
+		resExt := filepath.Ext(resBaseName)
+		resMD5Name, _ := gmd5.EncryptString(resBaseName)
+		srcPath := path.Join("/markdown", resMD5Name+resExt)
+		resPath := path.Join(pageDir, srcPath)
+
+		err = gfile.PutBytes(resPath, res)
+		if err != nil {
+			log.Printf("[err]PutBytes %s: %s", resPath, err)
+			s.SetAttr("src", resURL)
+			return
+		}
+		// 替换src
+		s.SetAttr("src", srcPath)
+	})
+	return content.Html()
+}
+
+// NewMarkdownConverter 创建html转markdown的转换器，标题自动降一级，支持删除线和表格
+//
+// createTime: 2026-10-18 09:20:35
+func NewMarkdownConverter() *md.Converter {
+	converter := md.NewConverter("", true, nil)
+	// md文档只能有一个一级标题，所以需要自动降级
+	converter.AddRules(md.Rule{
+		Filter: []string{"h1", "h2", "h3", "h4", "h5", "h6"},
+		Replacement: func(content string, selec *goquery.Selection, opt *md.Options) *string {
+			if strings.TrimSpace(content) == "" {
+				return nil
+			}
+
+			content = strings.Replace(content, "\n", " ", -1)
+			content = strings.Replace(content, "\r", " ", -1)
+			content = strings.Replace(content, `#`, `\#`, -1)
+			content = strings.TrimSpace(content)
+
+			insideLink := selec.ParentsFiltered("a").Length() > 0
+			if insideLink {
+				text := opt.StrongDelimiter + content + opt.StrongDelimiter
+				text = md.AddSpaceIfNessesary(selec, text)
+				return &text
+			}
+
+			node := goquery.NodeName(selec)
+			level, err := strconv.Atoi(node[1:])
+			if err != nil {
+				return nil
+			}
+
+			if opt.HeadingStyle == "setext" && level < 3 {
+				line := "-"
+				if level == 1 {
+					line = "="
+				}
+
+				underline := strings.Repeat(line, len(content))
+				return md.String("\n\n" + content + "\n" + underline + "\n\n")
+			}
+
+			prefix := strings.Repeat("#", level+1)
+			text := "\n\n" + prefix + " " + content + "\n\n"
+			return &text
+		},
+	})
+	converter.Use(plugin.Strikethrough(""))
+	converter.Use(ConverterTable())
+	return converter
+}
+
+// ConverterTable converts a html table (using hyphens and pipe characters) to a
+// visuall representation in markdown.
+//
+// Note: This Plugin overrides the default compatibility rules from `commonmark.go`.
+// Only use this Plugin in an environment that has extendeded the normal syntax,
+// like GitHub's Flavored Markdown.
+func ConverterTable() md.Plugin {
+	return func(c *md.Converter) []md.Rule {
+		c.Before(func(selec *goquery.Selection) {
+			selec.Find("caption").Each(func(i int, s *goquery.Selection) {
+				parent := s.Parent()
+				if !parent.Is("table") {
+					return
+				}
+
+				// move the caption from inside the table to after the table
+				parent.AfterSelection(s)
+			})
+		})
+
+		return []md.Rule{
+			{
+				Filter: []string{"table"},
+				Replacement: func(content string, selec *goquery.Selection, opt *md.Options) *string {
+					noHeader := selec.Find("thead").Length() == 0 && selec.Find("th").Length() == 0
+					if noHeader {
+						var maxCount int
+						selec.Find("tr").Each(func(i int, s *goquery.Selection) {
+							count := s.Children().Length()
+							if count > maxCount {
+								maxCount = count
+							}
+						})
+
+						// add an empty header, so that the table is recognized.
+						header := "|" + strings.Repeat("     |", maxCount)
+						divider := "|" + strings.Repeat(" --- |", maxCount)
+
+						content = header + "\n" + divider + content
+					}
+
+					content = "\n\n" + content + "\n\n"
+					return &content
+				},
+			},
+			{ // TableCell
+				Filter: []string{"th", "td"},
+				Replacement: func(content string, selec *goquery.Selection, opt *md.Options) *string {
+					// 有些表格的竖杠没有被处理
+					if selec.Find("code").Length() > 0 {
+						codeText := selec.Find("code").Text()
+						content = strings.ReplaceAll(content, codeText, strings.ReplaceAll(codeText, "|", "\\|"))
+					}
+					return md.String(getCellContent(content, selec))
+				},
+			},
+			{ // TableRow
+				Filter: []string{"tr"},
+				Replacement: func(content string, selec *goquery.Selection, opt *md.Options) *string {
+					borderCells := ""
+
+					if isHeadingRow(selec) {
+						selec.Children().Each(func(i int, s *goquery.Selection) {
+							border := "---"
+							if align, ok := s.Attr("align"); ok {
+								switch align {
+								case "left":
+									border = ":--"
+								case "right":
+									border = "--:"
+								case "center":
+									border = ":-:"
+								}
+							}
+
+							borderCells += getCellContent(border, s)
+						})
+					}
+
+					text := "\n" + content
+					if borderCells != "" {
+						text += "\n" + borderCells
+					}
+					return &text
+				},
+			},
+		}
+	}
+}
+
+// A tr is a heading row if:
+//   - the parent is a THEAD
+//   - or if its the first child of the TABLE or the first TBODY (possibly
+//     following a blank THEAD)
+//   - and every cell is a TH
+func isHeadingRow(s *goquery.Selection) bool {
+	parent := s.Parent()
+
+	if goquery.NodeName(parent) == "thead" {
+		return true
+	}
+
+	isTableOrBody := parent.Is("table") || parent.Is("tbody")
+
+	everyTH := true
+	s.Children().Each(func(i int, s *goquery.Selection) {
+		if goquery.NodeName(s) != "th" {
+			everyTH = false
+		}
+	})
+
+	if parent.Children().First().IsSelection(s) && isTableOrBody && everyTH {
+		return true
+	}
+
+	return false
+}
+func isFirstTbody(s *goquery.Selection) bool {
+	firstSibling := s.Siblings().Eq(0) // TODO: previousSibling
+	if s.Is("tbody") && firstSibling.Length() == 0 {
+		return true
+	}
+
+	return false
+}
+
+var newLineRe = regexp.MustCompile(`(\r?\n)+`)
+
+func getCellContent(content string, s *goquery.Selection) string {
+	content = strings.TrimSpace(content)
+	if s.Find("table").Length() == 0 {
+		// nested tables not found
+		content = newLineRe.ReplaceAllString(content, "<br />")
+	}
+	index := -1
+	for i, node := range s.Parent().Children().Nodes {
+		if s.IsNodes(node) {
+			index = i
+			break
+		}
+	}
+	prefix := " "
+	if index == 0 {
+		prefix = "| "
+	}
+	return prefix + content + " |"
+}
@@ -0,0 +1,24 @@
+package doc2pdf_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hailaz/doc2pdf"
+)
+
+// TestNewMarkdownConverter description
+//
+// createTime: 2026-10-18 09:21:49
+func TestNewMarkdownConverter(t *testing.T) {
+	markdown, err := doc2pdf.NewMarkdownConverter().ConvertString(
+		`<h1>标题</h1><p><del>旧</del></p><table><tr><th>名称</th></tr><tr><td>值</td></tr></table>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"## 标题", "~旧~", "| 名称 |", "| 值 |"} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("missing %q in\n%s", want, markdown)
+		}
+	}
+}
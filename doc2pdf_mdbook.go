@@ -0,0 +1,228 @@
+package doc2pdf
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/go-rod/rod"
+)
+
+// DownloadMdBook 下载mdbook或gitbook文档，mode为pdf或md
+//
+// createTime: 2026-10-18 09:31:47
+func DownloadMdBook(ctx context.Context, mainURL string, outputDir string, mode string, opts ...DocOption) (*RunSummary, error) {
+	return DownloadWithAdapter(ctx, &MdBookAdapter{}, mainURL, outputDir, mode, opts...)
+}
+
+func init() {
+	RegisterAdapter("mdbook", func() SiteAdapter {
+		return &MdBookAdapter{}
+	})
+}
+
+// MdBookAdapter mdbook和旧版gitbook适配器，章节编号会保留在书签标题中。
+// pdf模式下站点提供print.html时只打开一次打印页，逐章渲染
+type MdBookAdapter struct {
+	BaseAdapter
+	NoPrint  bool   // 不使用print.html，逐页打开渲染
+	printURL string // 菜单页中找到的print.html地址
+}
+
+// Name description
+func (a *MdBookAdapter) Name() string {
+	return "mdbook"
+}
+
+// Brief description
+func (a *MdBookAdapter) Brief() string {
+	return "mdbook/gitbook文档转换为pdf或markdown"
+}
+
+// Options description
+func (a *MdBookAdapter) Options() []AdapterOption {
+	return []AdapterOption{
+		{Name: "no-print", Brief: "不使用print.html，逐页打开渲染", Orphan: true},
+	}
+}
+
+// SetOption description
+func (a *MdBookAdapter) SetOption(name string, value string) error {
+	if name == "no-print" {
+		var err error
+		a.NoPrint, err = ParseBoolOption(name, value)
+		return err
+	}
+	return a.BaseAdapter.SetOption(name, value)
+}
+
+// MenuRootSelector mdbook和gitbook的目录
+func (a *MdBookAdapter) MenuRootSelector() string {
+	return "#sidebar ol.chapter, div.book-summary ul.summary"
+}
+
+// ParseMenu description
+func (a *MdBookAdapter) ParseMenu(doc *DocDownload, root *rod.Element, level int, dirPath string) ([]*DocNode, error) {
+	if !a.NoPrint {
+		if has, link, err := root.Page().Has(`a[href$="print.html"]`); err == nil && has {
+			a.printURL = sameSiteURL(doc, link)
+			log.Println("找到打印页", a.printURL)
+		}
+	}
+	menu, err := menuSelection(root)
+	if err != nil {
+		return nil, err
+	}
+	return doc.menuNodes(ParseMdBookMenu(menu), make(map[string]bool)), nil
+}
+
+// MarkdownOptions description
+func (a *MdBookAdapter) MarkdownOptions() *MarkdownOptions {
+	return &MarkdownOptions{
+		ContentSelector: "#content > main, section.markdown-section",
+		Strip: []string{
+			"div.buttons",
+			"button.clip-button",
+		},
+	}
+}
+
+// PrintOptions description
+func (a *MdBookAdapter) PrintOptions() *PrintOptions {
+	return &PrintOptions{PaperWidth: 15, PageHeight: 11, SinglePage: true}
+}
+
+// PreparePage 删除菜单栏、侧边栏、主题切换和搜索，固定使用浅色主题
+//
+// createTime: 2026-10-18 09:31:47
+func (a *MdBookAdapter) PreparePage(doc *DocDownload, page *rod.Page) error {
+	if err := RemoveElements(page,
+		// mdbook
+		"#menu-bar",
+		"#menu-bar-hover-placeholder",
+		"#sidebar",
+		"#theme-list",
+		"#search-wrapper",
+		"nav.nav-wrapper",
+		"nav.nav-wide-wrapper",
+		// gitbook
+		"div.book-summary",
+		"div.book-header",
+		"a.navigation",
+		"#book-search-input",
+	); err != nil {
+		return err
+	}
+	if _, err := page.Eval(`() => {
+		const html = document.documentElement;
+		['coal', 'navy', 'ayu', 'rust'].forEach(theme => html.classList.remove(theme));
+		html.classList.add('light');
+		html.classList.remove('sidebar-visible');
+	}`); err != nil {
+		return err
+	}
+	if err := AddStyle(page, `
+		.page-wrapper, .sidebar-visible .page-wrapper { margin-left: 0 !important; transform: none !important; }
+		.content main { max-width: none !important; }
+		.book-body { position: relative !important; left: 0 !important; }
+		.book-body .body-inner { position: static !important; overflow: visible !important; }
+		.page-inner { max-width: none !important; }
+		pre, pre code { white-space: pre-wrap !important; overflow-wrap: anywhere; }
+	`); err != nil {
+		return err
+	}
+	WaitImages(page, 30*time.Second)
+	return nil
+}
+
+// Exporter pdf模式下使用打印页逐章渲染，恢复和增量导出需要逐页校验，不使用打印页
+func (a *MdBookAdapter) Exporter(doc *DocDownload) Exporter {
+	if a.printURL == "" || doc.Mode != DocDownloadModePDF || doc.Resume || doc.Incremental {
+		return nil
+	}
+	return &PrintViewExporter{
+		URL:     a.printURL,
+		Split:   mdBookSplitChapters,
+		Prepare: a.PreparePage,
+		Print:   a.PrintOptions(),
+	}
+}
+
+// mdBookSplitChapters 打印页中的章节以分页div分隔，顺序与目录一致
+const mdBookSplitChapters = `() => {
+	const main = document.querySelector('#content > main') || document.querySelector('main');
+	if (!main) {
+		return 0;
+	}
+	const chapters = [];
+	let current = null;
+	Array.from(main.childNodes).forEach(node => {
+		const isBreak = node.nodeType === Node.ELEMENT_NODE && node.tagName === 'DIV' &&
+			node.children.length === 0 && (node.getAttribute('style') || '').includes('break-before: page');
+		if (isBreak) {
+			node.remove();
+			current = null;
+			return;
+		}
+		if (!current) {
+			current = document.createElement('section');
+			current.className = 'doc2pdf-chapter';
+			chapters.push(current);
+		}
+		current.appendChild(node);
+	});
+	chapters.forEach(chapter => main.appendChild(chapter));
+	return chapters.length;
+}`
+
+// ParseMdBookMenu 解析mdbook和gitbook的目录。mdbook的子目录在下一个li的ol.section中，
+// 部分标题和gitbook的分组标题作为只有书签的节点包含其后的章节
+//
+// createTime: 2026-10-18 09:31:47
+func ParseMdBookMenu(list *goquery.Selection) []MenuItem {
+	items := make([]MenuItem, 0)
+	part := -1
+	// siblings 当前部分标题下的章节，没有部分标题时为顶层章节
+	siblings := func() *[]MenuItem {
+		if part < 0 {
+			return &items
+		}
+		return &items[part].Children
+	}
+	list.Children().Each(func(_ int, li *goquery.Selection) {
+		if li.Is(".spacer, .divider") {
+			return
+		}
+		if li.Is(".part-title, .header") {
+			items = append(items, MenuItem{Title: menuText(li)})
+			part = len(items) - 1
+			return
+		}
+		sub := li.ChildrenFiltered("ol.section, ul.articles").First()
+		link := li.ChildrenFiltered("a, span, div").First()
+		if link.Length() > 0 && !link.Is("a") {
+			if a := link.Find("a").First(); a.Length() > 0 {
+				link = a
+			}
+		}
+		if link.Length() == 0 {
+			// mdbook的子目录单独放在一个li中，属于前一个章节
+			if chapters := siblings(); sub.Length() > 0 && len(*chapters) > 0 {
+				last := &(*chapters)[len(*chapters)-1]
+				last.Children = append(last.Children, ParseMdBookMenu(sub)...)
+			}
+			return
+		}
+		item := MenuItem{Title: menuText(link), Href: menuHref(link)}
+		// gitbook未显示编号时使用data-level
+		if level := li.AttrOr("data-level", ""); level != "" && link.Find("b, strong").Length() == 0 {
+			item.Title = level + ". " + item.Title
+		}
+		if sub.Length() > 0 {
+			item.Children = ParseMdBookMenu(sub)
+		}
+		*siblings() = append(*siblings(), item)
+	})
+	return items
+}
@@ -0,0 +1,150 @@
+package doc2pdf_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/hailaz/doc2pdf"
+)
+
+const mdBookSidebar = `<nav id="sidebar" class="sidebar"><div class="sidebar-scrollbox"><ol class="chapter">
+	<li class="chapter-item"><a href="intro.html"><strong aria-hidden="true">1.</strong> Intro</a></li>
+	<li class="chapter-item"><a href="guide.html"><strong aria-hidden="true">2.</strong> Guide</a></li>
+	<li><ol class="section">
+		<li class="chapter-item"><a href="guide/install.html"><strong aria-hidden="true">2.1.</strong> Install</a></li>
+	</ol></li>
+</ol></div></nav>
+<div id="menu-bar"><a href="print.html" title="Print this book"><i class="fa fa-print"></i></a></div>`
+
+const mdBookBreak = `<div style="break-before: page; page-break-before: always;"></div>`
+
+// mdBookPages print.html中的章节以分页div分隔
+func mdBookPages(chapters ...string) map[string]string {
+	page := func(content string) string {
+		return `<html class="light"><body>` + mdBookSidebar + `<div id="content"><main>` + content + `</main></div></body></html>`
+	}
+	return map[string]string{
+		"/index.html":         page(`<h1>Intro</h1>`),
+		"/intro.html":         page(`<h1>Intro</h1>`),
+		"/guide.html":         page(`<h1>Guide</h1>`),
+		"/guide/install.html": page(`<h1>Install</h1>`),
+		"/print.html":         page(strings.Join(chapters, mdBookBreak)),
+	}
+}
+
+// exportFixture 使用任务的导出器导出文档树，检查输出的pdf
+func exportFixture(t *testing.T, doc *doc2pdf.DocDownload, tree *doc2pdf.DocTree) {
+	t.Helper()
+	if err := doc.Exporter().Export(doc, tree); err != nil {
+		t.Fatal(err)
+	}
+	if !gfile.Exists(doc.OutputPDF()) {
+		t.Fatal("missing output pdf")
+	}
+	for _, node := range tree.Pages() {
+		if node.Err != nil {
+			t.Errorf("%s: %s", node.Title, node.Err)
+		}
+	}
+}
+
+// TestMdBookPrintView 找到print.html时按分页div拆分章节渲染，不再逐页打开
+//
+// createTime: 2026-10-18 09:31:47
+func TestMdBookPrintView(t *testing.T) {
+	doc, site := newFixtureSite(t, &doc2pdf.MdBookAdapter{}, mdBookPages(`<h1>Intro</h1>`, `<h1>Guide</h1>`, `<h1>Install</h1>`), "/index.html")
+	tree, err := doc.Discover(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := treeTitles(tree.Nodes); got != "1. Intro 2. Guide[2.1. Install]" {
+		t.Fatalf("tree = %s", got)
+	}
+	exporter, ok := doc.Exporter().(*doc2pdf.PrintViewExporter)
+	if !ok || !strings.HasSuffix(exporter.URL, "/print.html") {
+		t.Fatalf("exporter = %#v", doc.Exporter())
+	}
+	exportFixture(t, doc, tree)
+	if site.Requests("/print.html") != 1 {
+		t.Errorf("print.html requests = %d", site.Requests("/print.html"))
+	}
+	for _, p := range []string{"/intro.html", "/guide.html", "/guide/install.html"} {
+		if site.Requests(p) != 0 {
+			t.Errorf("%s should be rendered from print.html", p)
+		}
+	}
+}
+
+// TestMdBookPrintViewFallback 打印页章节数与目录不一致时逐页渲染
+//
+// createTime: 2026-10-18 09:31:47
+func TestMdBookPrintViewFallback(t *testing.T) {
+	doc, site := newFixtureSite(t, &doc2pdf.MdBookAdapter{}, mdBookPages(`<h1>Intro</h1>`, `<h1>Guide</h1>`), "/index.html")
+	tree, err := doc.Discover(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	exportFixture(t, doc, tree)
+	for _, p := range []string{"/print.html", "/intro.html", "/guide.html", "/guide/install.html"} {
+		if site.Requests(p) != 1 {
+			t.Errorf("%s requests = %d", p, site.Requests(p))
+		}
+	}
+}
+
+// TestParseMdBookMenu mdbook单独一个li的子目录属于前一个章节，gitbook用data-level补充编号
+//
+// createTime: 2026-10-18 09:31:47
+func TestParseMdBookMenu(t *testing.T) {
+	selector := (&doc2pdf.MdBookAdapter{}).MenuRootSelector()
+	items := doc2pdf.ParseMdBookMenu(menuFixture(t, mdBookSidebar, selector))
+	if got := menuTitles(items); got != "1. Intro 2. Guide[2.1. Install]" {
+		t.Errorf("mdbook menu = %s", got)
+	}
+	if items[1].Children[0].Href != "guide/install.html" {
+		t.Errorf("install = %q", items[1].Children[0].Href)
+	}
+
+	gitbook := `<div class="book-summary"><nav role="navigation"><ul class="summary">
+		<li class="header">Getting Started</li>
+		<li class="chapter" data-level="1.1"><a href="./">Introduction</a></li>
+		<li class="chapter" data-level="1.2"><a href="setup.html">Setup</a>
+			<ul class="articles"><li class="chapter" data-level="1.2.1"><a href="setup/linux.html">Linux</a></li></ul>
+		</li>
+		<li class="divider"></li>
+		<li class="chapter"><span>Draft</span></li>
+	</ul></nav></div>`
+	items = doc2pdf.ParseMdBookMenu(menuFixture(t, gitbook, selector))
+	if got := menuTitles(items); got != "Getting Started[1.1. Introduction 1.2. Setup[1.2.1. Linux] Draft]" {
+		t.Errorf("gitbook menu = %s", got)
+	}
+}
+
+// TestMdBookNoPrint 设置no-print时不使用打印页
+//
+// createTime: 2026-10-18 09:31:47
+func TestMdBookNoPrint(t *testing.T) {
+	adapter := &doc2pdf.MdBookAdapter{}
+	if err := adapter.SetOption("no-print", "true"); err != nil {
+		t.Fatal(err)
+	}
+	doc, site := newFixtureSite(t, adapter, mdBookPages(`<h1>Intro</h1>`, `<h1>Guide</h1>`, `<h1>Install</h1>`), "/index.html")
+	tree, err := doc.Discover(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := doc.Exporter().(*doc2pdf.PDFExporter); !ok {
+		t.Fatalf("exporter = %#v", doc.Exporter())
+	}
+	exportFixture(t, doc, tree)
+	if site.Requests("/print.html") != 0 {
+		t.Error("print.html should not be used")
+	}
+	for _, p := range []string{"/intro.html", "/guide.html", "/guide/install.html"} {
+		if site.Requests(p) != 1 {
+			t.Errorf("%s requests = %d", p, site.Requests(p))
+		}
+	}
+}
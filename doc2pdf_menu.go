@@ -0,0 +1,85 @@
+package doc2pdf
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/go-rod/rod"
+)
+
+// MenuItem 从侧边栏解析出的菜单项，分组标题的Href为空
+type MenuItem struct {
+	Title    string     `json:"title"`
+	Href     string     `json:"href"`
+	Children []MenuItem `json:"children"`
+}
+
+// menuSelection 复制菜单根节点的html交给goquery解析，链接改为完整地址。
+// 浏览器只负责展开菜单，菜单结构的解析不依赖浏览器
+//
+// createTime: 2026-10-18 09:23:12
+func menuSelection(root *rod.Element) (*goquery.Selection, error) {
+	res, err := root.Eval(`() => {
+		const clone = this.cloneNode(true);
+		clone.querySelectorAll('a[href]').forEach(a => a.setAttribute('href', a.href));
+		return clone.outerHTML;
+	}`)
+	if err != nil {
+		return nil, err
+	}
+	page, err := goquery.NewDocumentFromReader(strings.NewReader(res.Value.String()))
+	if err != nil {
+		return nil, err
+	}
+	return page.Find("body").Children().First(), nil
+}
+
+// menuText 元素的文本，连续空白合并为一个空格
+//
+// createTime: 2026-10-18 09:23:12
+func menuText(s *goquery.Selection) string {
+	return strings.Join(strings.Fields(s.Text()), " ")
+}
+
+// menuHref 元素的href，不是链接时返回空
+//
+// createTime: 2026-10-18 09:23:12
+func menuHref(s *goquery.Selection) string {
+	if !s.Is("a") {
+		return ""
+	}
+	href, _ := s.Attr("href")
+	return href
+}
+
+// sameSiteURL 链接的完整地址，去掉锚点，站外链接返回空
+//
+// createTime: 2026-10-18 09:31:47
+func sameSiteURL(doc *DocDownload, a *rod.Element) string {
+	href, err := a.Property("href")
+	if err != nil {
+		return ""
+	}
+	return doc.SameSiteURL(href.String())
+}
+
+// menuNodes 将菜单项转换为文档节点，站外链接和seen中已出现的页面不生成页面，
+// 同一页面内的锚点只保留第一个
+//
+// createTime: 2026-10-18 09:23:12
+func (doc *DocDownload) menuNodes(items []MenuItem, seen map[string]bool) []*DocNode {
+	nodes := make([]*DocNode, 0, len(items))
+	for _, item := range items {
+		node := &DocNode{Title: strings.Join(strings.Fields(item.Title), " ")}
+		if pageURL := doc.SameSiteURL(item.Href); pageURL != "" && !seen[pageURL] {
+			seen[pageURL] = true
+			node.URL = pageURL
+		}
+		node.Children = doc.menuNodes(item.Children, seen)
+		if node.Title == "" || (node.URL == "" && len(node.Children) == 0) {
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
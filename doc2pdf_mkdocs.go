@@ -0,0 +1,162 @@
+package doc2pdf
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/go-rod/rod"
+)
+
+// DownloadMkDocs 下载mkdocs material文档，mode为pdf或md
+//
+// createTime: 2026-10-18 09:23:12
+func DownloadMkDocs(ctx context.Context, mainURL string, outputDir string, mode string, opts ...DocOption) (*RunSummary, error) {
+	return DownloadWithAdapter(ctx, &MkDocsAdapter{}, mainURL, outputDir, mode, opts...)
+}
+
+func init() {
+	RegisterAdapter("mkdocs", func() SiteAdapter {
+		return &MkDocsAdapter{}
+	})
+}
+
+// MkDocsAdapter mkdocs material主题适配器
+type MkDocsAdapter struct {
+	BaseAdapter
+}
+
+// Name description
+func (a *MkDocsAdapter) Name() string {
+	return "mkdocs"
+}
+
+// Brief description
+func (a *MkDocsAdapter) Brief() string {
+	return "mkdocs material文档转换为pdf或markdown"
+}
+
+// MenuRootSelector description
+func (a *MkDocsAdapter) MenuRootSelector() string {
+	return "nav.md-nav--primary > ul.md-nav__list"
+}
+
+// ParseMenu description
+func (a *MkDocsAdapter) ParseMenu(doc *DocDownload, root *rod.Element, level int, dirPath string) ([]*DocNode, error) {
+	// 折叠的目录默认隐藏，全部展开后才能正确读取标题
+	if _, err := root.Eval(`() => {
+		this.querySelectorAll('input.md-nav__toggle').forEach(toggle => { toggle.checked = true; });
+	}`); err != nil {
+		return nil, fmt.Errorf("展开菜单失败: %w", err)
+	}
+	menu, err := menuSelection(root)
+	if err != nil {
+		return nil, err
+	}
+	return doc.menuNodes(ParseMkDocsMenu(menu), make(map[string]bool)), nil
+}
+
+// MarkdownOptions description
+func (a *MkDocsAdapter) MarkdownOptions() *MarkdownOptions {
+	return &MarkdownOptions{
+		ContentSelector: "article.md-content__inner",
+		Strip: []string{
+			"a.headerlink",
+			"a.md-content__button",
+			"aside.md-source-file",
+			"nav.md-tags",
+			"#__comments",
+		},
+	}
+}
+
+// PrintOptions description
+func (a *MkDocsAdapter) PrintOptions() *PrintOptions {
+	return &PrintOptions{PaperWidth: 15, PageHeight: 11, SinglePage: true}
+}
+
+// PreparePage 删除页头、页脚、侧边栏和搜索，展开内容标签页和折叠的提示块
+//
+// createTime: 2026-10-18 09:23:12
+func (a *MkDocsAdapter) PreparePage(doc *DocDownload, page *rod.Page) error {
+	if err := RemoveElements(page,
+		"header.md-header",
+		"nav.md-tabs",
+		"div.md-sidebar",
+		"footer.md-footer",
+		"div.md-search",
+		"div.md-banner",
+		"div.md-dialog",
+		"button.md-top",
+		"a.md-content__button",
+		"#__comments",
+	); err != nil {
+		return err
+	}
+	if err := AddStyle(page, `
+		.md-main__inner { margin-top: 0; }
+		.md-content { max-width: none; margin: 0; }
+		.md-content__inner { margin: 0 1rem; }
+		pre code { white-space: pre-wrap; overflow-wrap: anywhere; }
+		.tabbed-set > .tabbed-content > .tabbed-block { display: block !important; }
+		.tabbed-set > .tabbed-labels { display: none; }
+	`); err != nil {
+		return err
+	}
+	// 内容标签页全部显示时在每个标签内容前加上标签名
+	if _, err := page.Eval(`() => {
+		document.querySelectorAll('.tabbed-set').forEach(set => {
+			const labels = set.querySelectorAll(':scope > .tabbed-labels > label');
+			set.querySelectorAll(':scope > .tabbed-content > .tabbed-block').forEach((block, i) => {
+				if (labels[i]) {
+					const title = document.createElement('p');
+					title.innerHTML = '<strong>' + labels[i].innerText + '</strong>';
+					block.prepend(title);
+				}
+			});
+		});
+		document.querySelectorAll('details').forEach(details => { details.open = true; });
+	}`); err != nil {
+		return err
+	}
+	if err := ScrollToBottom(page); err != nil {
+		return err
+	}
+	WaitImages(page, 30*time.Second)
+	// 等待mermaid、代码高亮等脚本渲染完成
+	return page.WaitDOMStable(time.Second, 0.01)
+}
+
+// ParseMkDocsMenu 解析mkdocs material的导航，list为ul.md-nav__list
+//
+// createTime: 2026-10-18 09:23:12
+func ParseMkDocsMenu(list *goquery.Selection) []MenuItem {
+	items := make([]MenuItem, 0)
+	list.ChildrenFiltered("li.md-nav__item").Each(func(_ int, li *goquery.Selection) {
+		item := MenuItem{}
+		// 目录的索引页链接在div.md-nav__link中，普通页面链接是li的直接子元素
+		a := li.ChildrenFiltered("a.md-nav__link")
+		if a.Length() == 0 {
+			a = li.ChildrenFiltered("div.md-nav__link").ChildrenFiltered("a.md-nav__link")
+		}
+		if a.Length() > 0 {
+			item.Title = menuText(a.First())
+			item.Href = menuHref(a.First())
+		}
+		// 页内目录(md-nav--secondary)不作为子菜单
+		sub := li.ChildrenFiltered("nav.md-nav").Not(".md-nav--secondary").ChildrenFiltered("ul.md-nav__list")
+		if sub.Length() > 0 {
+			if item.Title == "" {
+				label := li.ChildrenFiltered("label.md-nav__link")
+				if label.Length() == 0 {
+					label = li.ChildrenFiltered("div.md-nav__link").ChildrenFiltered("label.md-nav__link")
+				}
+				item.Title = menuText(label.First())
+			}
+			item.Children = ParseMkDocsMenu(sub.First())
+		}
+		items = append(items, item)
+	})
+	return items
+}
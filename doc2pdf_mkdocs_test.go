@@ -0,0 +1,228 @@
+package doc2pdf_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/hailaz/doc2pdf"
+)
+
+// fixtureSite 提供静态页面的测试服务器，记录每个路径的请求次数
+type fixtureSite struct {
+	mu       sync.Mutex
+	requests map[string]int
+}
+
+// Requests 返回路径的请求次数
+func (s *fixtureSite) Requests(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[path]
+}
+
+// newFixtureDoc 启动提供静态页面的测试服务器，创建使用adapter的下载任务，没有浏览器时跳过测试
+//
+// createTime: 2026-10-18 09:23:12
+func newFixtureDoc(t *testing.T, adapter doc2pdf.SiteAdapter, pages map[string]string, index string) *doc2pdf.DocDownload {
+	doc, _ := newFixtureSite(t, adapter, pages, index)
+	return doc
+}
+
+// newFixtureSite 同 newFixtureDoc，同时返回测试服务器用于检查请求过的页面
+//
+// createTime: 2026-10-18 09:24:41
+func newFixtureSite(t *testing.T, adapter doc2pdf.SiteAdapter, pages map[string]string, index string) (*doc2pdf.DocDownload, *fixtureSite) {
+	t.Helper()
+	if _, exists := launcher.LookPath(); !exists {
+		t.Skip("未找到浏览器")
+	}
+	site := &fixtureSite{requests: make(map[string]int)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		site.mu.Lock()
+		site.requests[r.URL.Path]++
+		site.mu.Unlock()
+		content, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, content)
+	}))
+	t.Cleanup(server.Close)
+	doc, err := doc2pdf.NewDocDownload(server.URL+index, filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { doc.Close() })
+	doc.Adapter = adapter
+	if err := adapter.Init(doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc, site
+}
+
+// treeTitles 按层级返回文档树的标题，子节点用括号包含
+func treeTitles(nodes []*doc2pdf.DocNode) string {
+	s := ""
+	for i, node := range nodes {
+		if i > 0 {
+			s += " "
+		}
+		s += node.Title
+		if len(node.Children) > 0 {
+			s += "[" + treeTitles(node.Children) + "]"
+		}
+	}
+	return s
+}
+
+// menuFixture 用goquery从保存的页面中取出菜单根节点，不需要浏览器
+//
+// createTime: 2026-10-18 09:23:12
+func menuFixture(t *testing.T, page string, selector string) *goquery.Selection {
+	t.Helper()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := doc.Find(selector).First()
+	if root.Length() == 0 {
+		t.Fatalf("menu root %s not found", selector)
+	}
+	return root
+}
+
+// menuTitles 同 treeTitles，用于解析出的菜单项
+func menuTitles(items []doc2pdf.MenuItem) string {
+	s := ""
+	for i, item := range items {
+		if i > 0 {
+			s += " "
+		}
+		s += item.Title
+		if len(item.Children) > 0 {
+			s += "[" + menuTitles(item.Children) + "]"
+		}
+	}
+	return s
+}
+
+const mkDocsFixture = `<html><body>
+<header class="md-header">header</header>
+<div class="md-sidebar md-sidebar--primary"><nav class="md-nav md-nav--primary"><ul class="md-nav__list">
+	<li class="md-nav__item"><a class="md-nav__link" href="/">Home</a></li>
+	<li class="md-nav__item md-nav__item--nested">
+		<input class="md-nav__toggle" type="checkbox" id="nav-2">
+		<label class="md-nav__link" for="nav-2">Guide</label>
+		<nav class="md-nav"><ul class="md-nav__list">
+			<li class="md-nav__item"><a class="md-nav__link" href="/guide/install/">Install</a>
+				<nav class="md-nav md-nav--secondary"><ul class="md-nav__list">
+					<li class="md-nav__item"><a class="md-nav__link" href="/guide/install/#usage">Usage</a></li>
+				</ul></nav>
+			</li>
+		</ul></nav>
+	</li>
+	<li class="md-nav__item md-nav__item--nested">
+		<input class="md-nav__toggle" type="checkbox" id="nav-3">
+		<div class="md-nav__link md-nav__container"><a class="md-nav__link" href="/api/">API</a></div>
+		<nav class="md-nav"><ul class="md-nav__list">
+			<li class="md-nav__item"><a class="md-nav__link" href="/api/client/">Client</a></li>
+		</ul></nav>
+	</li>
+	<li class="md-nav__item"><a class="md-nav__link" href="https://github.com/example/docs">GitHub</a></li>
+</ul></nav></div>
+<article class="md-content__inner">
+	<details class="note"><summary>Note</summary><p>hidden</p></details>
+	<details class="tip"><summary>Tip</summary><p>hidden</p></details>
+</article>
+</body></html>`
+
+// TestMkDocsMenu 折叠的目录展开后解析，跳过页内目录和站外链接
+//
+// createTime: 2026-10-18 09:23:12
+func TestMkDocsMenu(t *testing.T) {
+	doc := newFixtureDoc(t, &doc2pdf.MkDocsAdapter{}, map[string]string{"/": mkDocsFixture}, "/")
+	tree, err := doc.Discover(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := treeTitles(tree.Nodes); got != "Home Guide[Install] API[Client]" {
+		t.Fatalf("tree = %s", got)
+	}
+	guide := tree.Nodes[1]
+	if guide.URL != "" || guide.Children[0].URL != doc.MainURL+"guide/install/" {
+		t.Errorf("guide = %s, install = %s", guide.URL, guide.Children[0].URL)
+	}
+	if tree.Nodes[2].URL != doc.MainURL+"api/" {
+		t.Errorf("api = %s", tree.Nodes[2].URL)
+	}
+
+	root, err := doc.GetMenuRoot((&doc2pdf.MkDocsAdapter{}).MenuRootSelector())
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := root.Eval(`() => this.querySelectorAll('input.md-nav__toggle:not(:checked)').length`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Value.Int() != 2 {
+		t.Fatalf("unchecked toggles before parse = %d", res.Value.Int())
+	}
+	if _, err := doc.Adapter.ParseMenu(doc, root, 0, doc.OutputDir()); err != nil {
+		t.Fatal(err)
+	}
+	if res, err = root.Eval(`() => this.querySelectorAll('input.md-nav__toggle:not(:checked)').length`); err != nil {
+		t.Fatal(err)
+	}
+	if res.Value.Int() != 0 {
+		t.Errorf("unchecked toggles after parse = %d", res.Value.Int())
+	}
+}
+
+// TestParseMkDocsMenu 目录使用label或索引页链接作为标题，页内目录不作为子菜单
+//
+// createTime: 2026-10-18 09:23:12
+func TestParseMkDocsMenu(t *testing.T) {
+	items := doc2pdf.ParseMkDocsMenu(menuFixture(t, mkDocsFixture, (&doc2pdf.MkDocsAdapter{}).MenuRootSelector()))
+	if got := menuTitles(items); got != "Home Guide[Install] API[Client] GitHub" {
+		t.Fatalf("menu = %s", got)
+	}
+	if items[1].Href != "" || items[1].Children[0].Href != "/guide/install/" || items[2].Href != "/api/" {
+		t.Errorf("guide = %q, install = %q, api = %q", items[1].Href, items[1].Children[0].Href, items[2].Href)
+	}
+}
+
+// TestMkDocsPreparePage 删除页头并展开所有折叠的提示块
+//
+// createTime: 2026-10-18 09:23:12
+func TestMkDocsPreparePage(t *testing.T) {
+	doc := newFixtureDoc(t, &doc2pdf.MkDocsAdapter{}, map[string]string{"/": mkDocsFixture}, "/")
+	page, err := doc.OpenPage(doc.MainURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer doc.ClosePage(page)
+	if err := doc.Adapter.PreparePage(doc, page); err != nil {
+		t.Fatal(err)
+	}
+	res, err := page.Eval(`() => ({
+		header: document.querySelectorAll('header.md-header, div.md-sidebar').length,
+		closed: document.querySelectorAll('details:not([open])').length,
+		details: document.querySelectorAll('details[open]').length,
+	})`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Value.Get("header").Int() != 0 || res.Value.Get("closed").Int() != 0 || res.Value.Get("details").Int() != 2 {
+		t.Errorf("page = %s", res.Value.JSON("", ""))
+	}
+}
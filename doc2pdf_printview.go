@@ -0,0 +1,81 @@
+package doc2pdf
+
+import (
+	"log"
+	"os"
+	"path"
+
+	"github.com/go-rod/rod"
+	"github.com/gogf/gf/v2/os/gfile"
+)
+
+// PrintViewExporter 使用站点的打印页(整本书或整个章节在一个页面中)渲染pdf，
+// 打印页只打开一次，逐章显示后打印到文档树中对应的输出文件，再交给pdf导出器合并。
+// 章节数与文档树页面数不一致或打印失败时，缺少的文件由pdf导出器逐页渲染
+type PrintViewExporter struct {
+	URL     string                                       // 打印页地址
+	Split   string                                       // js函数，给每个章节的根元素加上doc2pdf-chapter类并返回章节数
+	Prepare func(doc *DocDownload, page *rod.Page) error // 打印前处理页面
+	Print   *PrintOptions                                // 打印参数
+}
+
+// Export description
+//
+// createTime: 2026-10-18 09:34:49
+func (e *PrintViewExporter) Export(doc *DocDownload, tree *DocTree) error {
+	if err := e.renderChapters(doc, tree); err != nil {
+		log.Println("[err]打印页渲染失败，逐页渲染:", err)
+	}
+	return (&PDFExporter{}).Export(doc, tree)
+}
+
+// renderChapters 每次只显示一个章节打印到对应的输出文件
+//
+// createTime: 2026-10-18 09:34:49
+func (e *PrintViewExporter) renderChapters(doc *DocDownload, tree *DocTree) error {
+	// 打印页包含整本书，不受PageTimeout限制
+	page, err := doc.openPage(e.URL, 0)
+	if err != nil {
+		return err
+	}
+	defer doc.closePage(page, 0)
+	if e.Prepare != nil {
+		if err := e.Prepare(doc, page); err != nil {
+			return err
+		}
+	}
+	res, err := page.Eval(e.Split)
+	if err != nil {
+		return err
+	}
+	pages := tree.Pages()
+	if count := res.Value.Int(); count != len(pages) {
+		log.Printf("打印页章节数%d与目录页面数%d不一致，逐页渲染", count, len(pages))
+		return nil
+	}
+	for i, node := range pages {
+		if err := doc.Context().Err(); err != nil {
+			return err
+		}
+		if gfile.Exists(node.Path) {
+			continue
+		}
+		if _, err := page.Eval(`(index) => {
+			document.querySelectorAll('.doc2pdf-chapter').forEach((chapter, i) => {
+				chapter.style.display = i === index ? '' : 'none';
+			});
+			window.scrollTo(0, 0);
+		}`, i); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(path.Dir(node.Path), os.ModePerm); err != nil {
+			return err
+		}
+		log.Println("从打印页渲染", node.Title, node.Path)
+		if err := PageToPDFWithOptions(page, node.Path, e.Print); err != nil {
+			log.Printf("[err]打印章节失败 %s: %s", node.Path, err)
+			os.Remove(node.Path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package doc2pdf
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/go-rod/rod"
+	"github.com/gogf/gf/v2/os/gfile"
+)
+
+// Exporter 导出器，根据文档树生成输出
+type Exporter interface {
+	Export(doc *DocDownload, tree *DocTree) error
+}
+
+// PDFExporter 渲染pdf并合并为带书签的单个文件
+type PDFExporter struct{}
+
+// Export description
+//
+// createTime: 2026-10-18 09:12:02
+func (e *PDFExporter) Export(doc *DocDownload, tree *DocTree) error {
+	doc.RenderPages(tree)
+	if err := doc.Context().Err(); err != nil {
+		return err
+	}
+
+	log.Println("判断是否合并文件")
+	doc.fileList = doc.fileList[:0]
+	for _, node := range tree.Pages() {
+		if node.Err == nil {
+			doc.fileList = append(doc.fileList, node.Path)
+		}
+	}
+	if len(doc.fileList) == 0 {
+		log.Println("没有可合并的文件")
+		return nil
+	}
+	// 插入到开头的封面和目录页，书签页码需要加上
+	bookmarks := tree.Bookmarks()
+	front := doc.renderFrontMatter(tree)
+	defer front.remove()
+	doc.fileList = append(front.files, doc.fileList...)
+	if err := doc.MrPDF(); err != nil {
+		return err
+	}
+
+	doc.bookmark = shiftBookmarks(bookmarks, front.pages)
+	if err := doc.AddBookmarks(); err != nil {
+		return NewPageError(StageBookmark, "", doc.OutputPDF(), err)
+	}
+	if front.tocThru > 0 {
+		if err := LinkTOCPages(doc.OutputPDF(), front.tocFrom, front.tocThru); err != nil {
+			log.Println("[err]目录页添加链接失败:", err)
+		}
+	}
+	doc.stampPDF(front.pages)
+
+	return doc.SplitPDF()
+}
+
+// MarkdownExporter 将每个页面保存为markdown
+type MarkdownExporter struct{}
+
+// Export description
+//
+// createTime: 2026-10-18 09:12:02
+func (e *MarkdownExporter) Export(doc *DocDownload, tree *DocTree) error {
+	doc.RenderPages(tree)
+	return doc.Context().Err()
+}
+
+// Exporter 返回当前模式的导出器，适配器提供导出器时优先使用
+//
+// createTime: 2026-10-18 09:12:02
+func (doc *DocDownload) Exporter() Exporter {
+	if e := doc.Adapter.Exporter(doc); e != nil {
+		return e
+	}
+	if doc.Mode == DocDownloadModeMD {
+		return &MarkdownExporter{}
+	}
+	return &PDFExporter{}
+}
+
+// WithWorkers 设置同时渲染的页面数
+//
+// createTime: 2026-10-18 09:10:44
+func WithWorkers(n int) DocOption {
+	return func(doc *DocDownload) {
+		doc.Workers = n
+	}
+}
+
+// RenderPages 使用页面池并发渲染文档树中的所有页面，渲染结果记录在节点上
+//
+// createTime: 2026-10-18 09:10:44
+func (doc *DocDownload) RenderPages(tree *DocTree) {
+	workers := doc.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	pages := tree.Pages()
+	log.Printf("开始渲染%d个页面，并发数%d", len(pages), workers)
+	doc.pagePool = rod.NewPagePool(workers)
+	defer func() {
+		doc.pagePool.Cleanup(func(p *rod.Page) {
+			p.Context(context.Background()).Close()
+		})
+		doc.pagePool = nil
+	}()
+
+	nodes := make(chan *DocNode)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for node := range nodes {
+				doc.renderPage(node)
+			}
+		}()
+	}
+	ctx := doc.Context()
+send:
+	for _, node := range pages {
+		select {
+		case nodes <- node:
+		case <-ctx.Done():
+			break send
+		}
+	}
+	close(nodes)
+	wg.Wait()
+}
+
+// renderPage 渲染单个页面
+//
+// createTime: 2026-10-18 09:10:44
+func (doc *DocDownload) renderPage(node *DocNode) {
+	defer doc.recordPage(node)
+	if doc.reusePage(node) {
+		log.Println("使用已下载文件", node.Path)
+		doc.AddResult(node.URL, node.Path, nil)
+		return
+	}
+	if doc.Mode == DocDownloadModePDF {
+		node.PageCount, node.Err = doc.SavePDFPage(node.Path, node.URL)
+		if node.Err == nil {
+			log.Printf("当前文件页数%d： %s", node.PageCount, node.Path)
+		}
+		return
+	}
+	if err := doc.Context().Err(); err != nil {
+		node.Err = err
+		return
+	}
+	node.Err = doc.SaveMD(node.Path, node.URL)
+	if node.Err == nil && node.FrontMatter != "" {
+		contents := gfile.GetContents(node.Path)
+		if !strings.HasPrefix(contents, "---") {
+			if err := gfile.PutContents(node.Path, node.FrontMatter+contents); err != nil {
+				log.Printf("[err]PutContents: %s", err)
+			}
+		}
+	}
+	doc.AddResult(node.URL, node.Path, node.Err)
+}
+
+// reusePage 判断是否可以使用上次的输出文件
+//
+// createTime: 2026-10-18 09:18:43
+func (doc *DocDownload) reusePage(node *DocNode) bool {
+	if doc.Context().Err() != nil {
+		return false
+	}
+	if doc.Incremental {
+		return doc.reuseUnchanged(node)
+	}
+	if doc.Resume && doc.manifest != nil {
+		return doc.manifest.Reuse(node)
+	}
+	return false
+}
+
+// recordPage 将页面结果写入清单，任务取消导致的失败不记录
+//
+// createTime: 2026-10-18 09:16:33
+func (doc *DocDownload) recordPage(node *DocNode) {
+	if doc.manifest == nil || doc.Context().Err() != nil {
+		return
+	}
+	doc.manifest.Record(node)
+	if err := doc.manifest.Save(); err != nil {
+		log.Println("[err]保存清单失败:", err)
+	}
+}
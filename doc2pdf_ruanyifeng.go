@@ -1,14 +1,13 @@
 package doc2pdf
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"path"
 	"time"
 
 	"github.com/go-rod/rod"
-	"github.com/pdfcpu/pdfcpu/pkg/api"
-	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
 )
 
 // DownloadRuanyifengWeekly description
@@ -16,8 +15,8 @@ import (
 // createTime: 2023-12-07 16:26:50
 //
 // author: hailaz
-func DownloadRuanyifengWeekly() {
-	DownloadRuanyifeng("http://www.ruanyifeng.com/blog/weekly/", "./output/ruanyifeng")
+func DownloadRuanyifengWeekly(ctx context.Context) (*RunSummary, error) {
+	return DownloadRuanyifeng(ctx, "http://www.ruanyifeng.com/blog/weekly/", "./output/ruanyifeng")
 }
 
 // DownloadRuanyifeng 下载文档
@@ -25,17 +24,59 @@ func DownloadRuanyifengWeekly() {
 // createTime: 2023-07-27 15:26:56
 //
 // author: hailaz
-func DownloadRuanyifeng(mainURL string, outputDir string) {
-	doc := NewDocDownload(mainURL, outputDir)
-	doc.SavePDFBefore = func(page *rod.Page) {
-		time.Sleep(time.Second * 1)
-	}
-	doc.MenuRootSelector = "div#alpha-inner"
-	doc.ParseMenu = ParseRuanyifengMenu
-	// doc.MergePDFNums = 10
-	doc.Start()
+func DownloadRuanyifeng(ctx context.Context, mainURL string, outputDir string, opts ...DocOption) (*RunSummary, error) {
 	// 复制文件到其它目录
 	// log.Println(doc.Move("./dist"))
+	return DownloadWithAdapter(ctx, &RuanyifengAdapter{}, mainURL, outputDir, DocDownloadModePDF, opts...)
+}
+
+func init() {
+	RegisterAdapter("ruanyifeng", func() SiteAdapter {
+		return &RuanyifengAdapter{}
+	})
+}
+
+// RuanyifengAdapter 阮一峰博客适配器
+type RuanyifengAdapter struct {
+	BaseAdapter
+}
+
+// Name description
+func (a *RuanyifengAdapter) Name() string {
+	return "ruanyifeng"
+}
+
+// Brief description
+func (a *RuanyifengAdapter) Brief() string {
+	return "阮一峰周刊转换为pdf"
+}
+
+// MenuRootSelector description
+func (a *RuanyifengAdapter) MenuRootSelector() string {
+	return "div#alpha-inner"
+}
+
+// ParseMenu description
+func (a *RuanyifengAdapter) ParseMenu(doc *DocDownload, root *rod.Element, level int, dirPath string) ([]*DocNode, error) {
+	return ParseRuanyifengMenu(doc, root, level, dirPath), nil
+}
+
+// MarkdownOptions description
+func (a *RuanyifengAdapter) MarkdownOptions() *MarkdownOptions {
+	return &MarkdownOptions{
+		ContentSelector: "article.hentry",
+		Strip:           []string{"div.asset-footer", "#share_button"},
+	}
+}
+
+// PreparePage description
+func (a *RuanyifengAdapter) PreparePage(doc *DocDownload, page *rod.Page) error {
+	select {
+	case <-time.After(time.Second * 1):
+		return nil
+	case <-page.GetContext().Done():
+		return page.GetContext().Err()
+	}
 }
 
 // ParseRuanyifengMenu 解析菜单
@@ -43,14 +84,15 @@ func DownloadRuanyifeng(mainURL string, outputDir string) {
 // createTime: 2023-07-11 16:13:27
 //
 // author: hailaz
-func ParseRuanyifengMenu(doc *DocDownload, root *rod.Element, level int, dirPath string, bms *[]pdfcpu.Bookmark) {
+func ParseRuanyifengMenu(doc *DocDownload, root *rod.Element, level int, dirPath string) []*DocNode {
+	nodes := make([]*DocNode, 0)
 	index := 0
 	// log.Println("ParseRuanyifengMenu", root.MustText())
 	// 循环当前节点的li
 	ulList, err := root.Elements("ul")
 	if err != nil {
 		log.Printf("[err]: %s", err)
-		return
+		return nodes
 	}
 	for _, ul := range ulList {
 		liList, err := ul.Elements("li")
@@ -61,6 +103,9 @@ func ParseRuanyifengMenu(doc *DocDownload, root *rod.Element, level int, dirPath
 		// log.Printf("len %d", len(liList))
 
 		for _, li := range liList {
+			if doc.Context().Err() != nil {
+				return nodes
+			}
 			// 获取当前节点的a标签
 			a, err := li.Element("a")
 			if err != nil {
@@ -80,11 +125,6 @@ func ParseRuanyifengMenu(doc *DocDownload, root *rod.Element, level int, dirPath
 			}
 			// log.Printf("title: %s\n", text)
 
-			*bms = append(*bms, pdfcpu.Bookmark{
-				Title:    text,
-				PageFrom: doc.pageFrom,
-			})
-
 			{
 				// 拼接完整的url
 				url := *href
@@ -95,17 +135,15 @@ func ParseRuanyifengMenu(doc *DocDownload, root *rod.Element, level int, dirPath
 
 				// fmt.Printf("%s[%s](%s)\n", strings.Repeat("--", level), text, url)
 				// 保存pdf
-				fileName := fmt.Sprintf("%s.pdf", text)
-				doc.fileList = append(doc.fileList, path.Join(dirPath, fileName))
-				doc.SavePDF(path.Join(dirPath, fileName), url)
-
-				page, _ := api.PageCountFile(path.Join(dirPath, fileName))
-				doc.pageFrom = doc.pageFrom + page
-
-				log.Printf("文档累计页数%d，当前文件页数%d： %s\n", doc.pageFrom, page, path.Join(dirPath, fileName))
-
+				fileName := fmt.Sprintf("%s%s", text, doc.FileExt())
+				nodes = append(nodes, &DocNode{
+					Title: text,
+					URL:   url,
+					Path:  path.Join(dirPath, fileName),
+				})
 			}
 			index++
 		}
 	}
+	return nodes
 }
@@ -0,0 +1,466 @@
+package doc2pdf
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+)
+
+// maxSitemapDepth sitemap索引的最大嵌套层数
+const maxSitemapDepth = 3
+
+// DownloadSitemap 根据sitemap.xml下载文档，mode为pdf或md
+//
+// createTime: 2026-10-18 09:36:25
+func DownloadSitemap(ctx context.Context, mainURL string, outputDir string, mode string, opts ...DocOption) (*RunSummary, error) {
+	return DownloadWithAdapter(ctx, &SitemapAdapter{}, mainURL, outputDir, mode, opts...)
+}
+
+func init() {
+	RegisterAdapter("sitemap", func() SiteAdapter {
+		return &SitemapAdapter{}
+	})
+}
+
+// SitemapAdapter 没有可识别侧边栏的站点，从sitemap.xml读取入口地址路径下的页面，
+// 按地址的路径层级生成文档树
+type SitemapAdapter struct {
+	BaseAdapter
+	Sitemap    string   // sitemap地址，默认为站点根目录的sitemap.xml
+	Include    []string // 包含的路径规则
+	Exclude    []string // 排除的路径规则
+	Content    string   // 正文选择器
+	Remove     string   // 打印前删除的元素选择器
+	FetchTitle bool     // 下载页面读取标题，否则使用路径名作为标题
+
+	filter  *SitemapFilter
+	lastMod map[string]string
+}
+
+// Name description
+func (a *SitemapAdapter) Name() string {
+	return "sitemap"
+}
+
+// Brief description
+func (a *SitemapAdapter) Brief() string {
+	return "根据sitemap.xml导出入口地址路径下的页面，按路径生成目录"
+}
+
+// Options description
+func (a *SitemapAdapter) Options() []AdapterOption {
+	return []AdapterOption{
+		{Name: "sitemap", Brief: "sitemap地址，默认为站点根目录的sitemap.xml，支持sitemap索引和.gz"},
+		{Name: "include", Brief: "包含的路径规则，逗号分隔，支持glob(*不跨目录，**跨目录)，re:开头为正则"},
+		{Name: "exclude", Brief: "排除的路径规则，格式同include"},
+		{Name: "content", Brief: "正文选择器，默认为body"},
+		{Name: "remove", Brief: "打印前删除的元素选择器"},
+		{Name: "fetch-title", Brief: "下载页面读取标题，否则使用路径名作为标题", Orphan: true},
+	}
+}
+
+// SetOption description
+func (a *SitemapAdapter) SetOption(name string, value string) error {
+	switch name {
+	case "sitemap":
+		a.Sitemap = value
+	case "include":
+		a.Include = splitPatterns(value)
+	case "exclude":
+		a.Exclude = splitPatterns(value)
+	case "content":
+		a.Content = value
+	case "remove":
+		a.Remove = value
+	case "fetch-title":
+		var err error
+		a.FetchTitle, err = ParseBoolOption(name, value)
+		return err
+	default:
+		return a.BaseAdapter.SetOption(name, value)
+	}
+	return nil
+}
+
+// Init 入口地址的路径作为前缀，只导出前缀下的页面
+func (a *SitemapAdapter) Init(doc *DocDownload) error {
+	u, err := url.Parse(doc.MainURL)
+	if err != nil {
+		return err
+	}
+	prefix := u.Path
+	if strings.HasSuffix(prefix, ".xml") || strings.HasSuffix(prefix, ".xml.gz") {
+		// 入口地址就是sitemap
+		if a.Sitemap == "" {
+			a.Sitemap = doc.MainURL
+		}
+		prefix = path.Dir(prefix)
+	}
+	if a.Sitemap == "" {
+		a.Sitemap = doc.baseURL + "/sitemap.xml"
+	}
+	a.filter, err = NewSitemapFilter(prefix, a.Include, a.Exclude)
+	return err
+}
+
+// ParseMenu 不需要菜单页，root为nil
+func (a *SitemapAdapter) ParseMenu(doc *DocDownload, root *rod.Element, level int, dirPath string) ([]*DocNode, error) {
+	entries, err := a.fetchSitemap(doc, a.Sitemap, 0, make(map[string]bool))
+	if err != nil {
+		return nil, err
+	}
+	pageURLs := make([]string, 0, len(entries))
+	a.lastMod = make(map[string]string)
+	for _, entry := range entries {
+		// /a/和/a/index.html是同一个页面
+		pageURL := sitemapPageURL(doc.SameSiteURL(entry.Loc))
+		if pageURL == "" || !a.filter.Match(pageURL) {
+			continue
+		}
+		if _, ok := a.lastMod[pageURL]; ok {
+			continue
+		}
+		a.lastMod[pageURL] = entry.LastMod
+		pageURLs = append(pageURLs, pageURL)
+	}
+	log.Printf("sitemap中匹配到%d个页面", len(pageURLs))
+	nodes := SitemapNodes(pageURLs, a.filter.Prefix)
+	if a.FetchTitle {
+		walkNodes(nodes, 0, func(node *DocNode, level int) bool {
+			if node.URL == "" || doc.Context().Err() != nil {
+				return true
+			}
+			if title := fetchTitle(doc, node.URL); title != "" {
+				node.Title = title
+			}
+			return true
+		})
+	}
+	return nodes, doc.Context().Err()
+}
+
+// fetchSitemap 下载并解析sitemap，sitemap索引会递归读取
+//
+// createTime: 2026-10-18 09:36:25
+func (a *SitemapAdapter) fetchSitemap(doc *DocDownload, sitemapURL string, depth int, visited map[string]bool) ([]SitemapEntry, error) {
+	if err := doc.Context().Err(); err != nil {
+		return nil, err
+	}
+	if visited[sitemapURL] || depth > maxSitemapDepth {
+		return nil, nil
+	}
+	visited[sitemapURL] = true
+	log.Println("读取sitemap", sitemapURL)
+	data, err := doc.FetchBytes(sitemapURL)
+	if err != nil {
+		return nil, fmt.Errorf("读取sitemap失败 %s: %w", sitemapURL, err)
+	}
+	urls, sitemaps, err := ParseSitemap(data)
+	if err != nil {
+		return nil, fmt.Errorf("解析sitemap失败 %s: %w", sitemapURL, err)
+	}
+	for _, sitemap := range sitemaps {
+		children, err := a.fetchSitemap(doc, sitemap.Loc, depth+1, visited)
+		if err != nil {
+			return nil, err
+		}
+		urls = append(urls, children...)
+	}
+	return urls, nil
+}
+
+// MarkdownOptions description
+func (a *SitemapAdapter) MarkdownOptions() *MarkdownOptions {
+	opt := &MarkdownOptions{ContentSelector: "body"}
+	if a.Content != "" {
+		opt.ContentSelector = a.Content
+	}
+	if a.Remove != "" {
+		opt.Strip = []string{a.Remove}
+	}
+	return opt
+}
+
+// PreparePage description
+//
+// createTime: 2026-10-18 09:36:25
+func (a *SitemapAdapter) PreparePage(doc *DocDownload, page *rod.Page) error {
+	if a.Remove != "" {
+		if err := RemoveElements(page, a.Remove); err != nil {
+			return err
+		}
+	}
+	WaitImages(page, 30*time.Second)
+	return nil
+}
+
+// PrintOptions description
+func (a *SitemapAdapter) PrintOptions() *PrintOptions {
+	return &PrintOptions{PaperWidth: 15, PageHeight: 11, SinglePage: true}
+}
+
+// Fingerprint 优先使用sitemap中的lastmod
+func (a *SitemapAdapter) Fingerprint(doc *DocDownload, pageURL string) (string, error) {
+	if lastMod := a.lastMod[pageURL]; lastMod != "" {
+		return "lastmod:" + lastMod, nil
+	}
+	return a.BaseAdapter.Fingerprint(doc, pageURL)
+}
+
+// SitemapEntry sitemap中的一个地址
+type SitemapEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+// sitemapXML 同时兼容urlset和sitemapindex
+type sitemapXML struct {
+	URLs     []SitemapEntry `xml:"url"`
+	Sitemaps []SitemapEntry `xml:"sitemap"`
+}
+
+// ParseSitemap 解析sitemap，返回页面地址和sitemap索引中的子sitemap地址，支持gzip压缩
+//
+// createTime: 2026-10-18 09:36:25
+func ParseSitemap(data []byte) (urls []SitemapEntry, sitemaps []SitemapEntry, err error) {
+	if len(data) > 2 && data[0] == 0x1f && data[1] == 0x8b {
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, nil, err
+		}
+		defer reader.Close()
+		if data, err = io.ReadAll(reader); err != nil {
+			return nil, nil, err
+		}
+	}
+	var sitemap sitemapXML
+	if err := xml.Unmarshal(data, &sitemap); err != nil {
+		return nil, nil, err
+	}
+	trim := func(entries []SitemapEntry) []SitemapEntry {
+		res := make([]SitemapEntry, 0, len(entries))
+		for _, entry := range entries {
+			entry.Loc = strings.TrimSpace(entry.Loc)
+			entry.LastMod = strings.TrimSpace(entry.LastMod)
+			if entry.Loc != "" {
+				res = append(res, entry)
+			}
+		}
+		return res
+	}
+	return trim(sitemap.URLs), trim(sitemap.Sitemaps), nil
+}
+
+// SitemapFilter 按路径前缀和包含、排除规则过滤地址，包含规则为空时包含前缀下的全部页面
+type SitemapFilter struct {
+	Prefix  string // 路径前缀，以/结尾
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+// NewSitemapFilter 规则以re:开头时为正则，否则为glob，*不跨目录，**跨目录
+//
+// createTime: 2026-10-18 09:36:25
+func NewSitemapFilter(prefix string, include []string, exclude []string) (*SitemapFilter, error) {
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	f := &SitemapFilter{Prefix: prefix}
+	var err error
+	if f.include, err = compilePatterns(include); err != nil {
+		return nil, err
+	}
+	if f.exclude, err = compilePatterns(exclude); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Match 判断地址的路径是否符合规则
+//
+// createTime: 2026-10-18 09:36:25
+func (f *SitemapFilter) Match(pageURL string) bool {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return false
+	}
+	p := u.Path
+	if p == "" {
+		p = "/"
+	}
+	if !strings.HasPrefix(p, f.Prefix) && p+"/" != f.Prefix {
+		return false
+	}
+	for _, re := range f.exclude {
+		if re.MatchString(p) {
+			return false
+		}
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, re := range f.include {
+		if re.MatchString(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// compilePatterns description
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		expr := strings.TrimPrefix(pattern, "re:")
+		if expr == pattern {
+			expr = globToRegexp(pattern)
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("规则错误 %s: %w", pattern, err)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+// globToRegexp glob转换为匹配完整路径的正则
+func globToRegexp(glob string) string {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				sb.WriteString(".*")
+				i++
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+	return sb.String()
+}
+
+// splitPatterns description
+func splitPatterns(value string) []string {
+	patterns := make([]string, 0)
+	for _, pattern := range strings.Split(value, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// SitemapNodes 按地址中前缀之后的路径层级生成文档节点，顺序与sitemap一致。
+// 没有页面的中间目录只生成书签，目录本身有页面(/a/或/a/index.html)时目录节点即为该页面，
+// 地址统一为/a/
+//
+// createTime: 2026-10-18 09:36:25
+func SitemapNodes(pageURLs []string, prefix string) []*DocNode {
+	root := &DocNode{}
+	dirs := map[string]*DocNode{"": root}
+	home := false
+	for _, pageURL := range pageURLs {
+		pageURL = sitemapPageURL(pageURL)
+		u, err := url.Parse(pageURL)
+		if err != nil {
+			continue
+		}
+		rel := strings.Trim(strings.TrimPrefix(u.Path, strings.TrimSuffix(prefix, "/")), "/")
+		segments := make([]string, 0)
+		for _, seg := range strings.Split(rel, "/") {
+			if seg != "" {
+				segments = append(segments, seg)
+			}
+		}
+		if len(segments) == 0 {
+			// 前缀目录本身的页面
+			if !home {
+				home = true
+				root.Children = append([]*DocNode{{Title: "首页", URL: pageURL}}, root.Children...)
+			}
+			continue
+		}
+		parent := root
+		for i, seg := range segments {
+			key := strings.Join(segments[:i+1], "/")
+			node, ok := dirs[key]
+			if !ok {
+				node = &DocNode{Title: sitemapTitle(seg)}
+				dirs[key] = node
+				parent.Children = append(parent.Children, node)
+			}
+			parent = node
+		}
+		if parent.URL == "" {
+			parent.URL = pageURL
+		}
+	}
+	return root.Children
+}
+
+// sitemapPageURL 去掉地址末尾的index.html，目录页统一使用目录地址
+//
+// createTime: 2026-10-18 09:36:25
+func sitemapPageURL(pageURL string) string {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return pageURL
+	}
+	dir, file := path.Split(u.Path)
+	if file != "index.html" && file != "index.htm" {
+		return pageURL
+	}
+	u.Path = dir
+	u.RawPath = ""
+	return u.String()
+}
+
+// sitemapTitle 路径名转换为标题，去掉扩展名，连字符和下划线替换为空格
+func sitemapTitle(seg string) string {
+	if unescaped, err := url.PathUnescape(seg); err == nil {
+		seg = unescaped
+	}
+	for _, ext := range []string{".html", ".htm", ".php", ".aspx"} {
+		seg = strings.TrimSuffix(seg, ext)
+	}
+	title := strings.Join(strings.FieldsFunc(seg, func(r rune) bool {
+		return r == '-' || r == '_'
+	}), " ")
+	if title == "" {
+		return seg
+	}
+	return title
+}
+
+// fetchTitle 页面的h1，没有时使用title
+func fetchTitle(doc *DocDownload, pageURL string) string {
+	page, err := doc.FetchDocument(pageURL)
+	if err != nil {
+		log.Printf("[err]读取标题失败 %s: %s", pageURL, err)
+		return ""
+	}
+	title := strings.TrimSpace(page.Find("h1").First().Text())
+	if title == "" {
+		title = strings.TrimSpace(page.Find("title").First().Text())
+	}
+	return strings.Join(strings.Fields(title), " ")
+}
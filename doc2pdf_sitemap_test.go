@@ -0,0 +1,109 @@
+package doc2pdf_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/hailaz/doc2pdf"
+)
+
+// TestParseSitemap description
+//
+// createTime: 2026-10-18 09:36:25
+func TestParseSitemap(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc> https://example.com/docs/ </loc><lastmod>2026-10-01</lastmod></url>
+  <url><loc>https://example.com/docs/guide/start.html</loc></url>
+</urlset>`)
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write(data)
+	w.Close()
+	for _, input := range [][]byte{data, buf.Bytes()} {
+		urls, sitemaps, err := doc2pdf.ParseSitemap(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(urls) != 2 || len(sitemaps) != 0 {
+			t.Fatalf("got %d urls, %d sitemaps", len(urls), len(sitemaps))
+		}
+		if urls[0].Loc != "https://example.com/docs/" || urls[0].LastMod != "2026-10-01" {
+			t.Errorf("unexpected entry %+v", urls[0])
+		}
+	}
+
+	_, sitemaps, err := doc2pdf.ParseSitemap([]byte(`<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>https://example.com/sitemap-docs.xml.gz</loc></sitemap>
+</sitemapindex>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sitemaps) != 1 || sitemaps[0].Loc != "https://example.com/sitemap-docs.xml.gz" {
+		t.Errorf("unexpected sitemaps %+v", sitemaps)
+	}
+}
+
+// TestSitemapFilter description
+//
+// createTime: 2026-10-18 09:36:25
+func TestSitemapFilter(t *testing.T) {
+	f, err := doc2pdf.NewSitemapFilter("/docs", []string{"/docs/guide/**", "re:^/docs/api/v[0-9]+/$"}, []string{"/docs/guide/*/draft-*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]bool{
+		"https://example.com/docs/guide/start.html":     true,
+		"https://example.com/docs/guide/a/b.html":       true,
+		"https://example.com/docs/guide/a/draft-b.html": false,
+		"https://example.com/docs/api/v2/":              true,
+		"https://example.com/docs/api/v2/list.html":     false,
+		"https://example.com/docs-old/guide/start.html": false,
+		"https://example.com/blog/guide/start.html":     false,
+	}
+	for pageURL, want := range cases {
+		if got := f.Match(pageURL); got != want {
+			t.Errorf("Match(%q) = %v, want %v", pageURL, got, want)
+		}
+	}
+	if _, err := doc2pdf.NewSitemapFilter("/", []string{"re:("}, nil); err == nil {
+		t.Error("invalid regexp should fail")
+	}
+}
+
+// TestSitemapNodes description
+//
+// createTime: 2026-10-18 09:36:25
+func TestSitemapNodes(t *testing.T) {
+	nodes := doc2pdf.SitemapNodes([]string{
+		"https://example.com/docs/guide/getting-started.html",
+		"https://example.com/docs/",
+		"https://example.com/docs/guide/index.html",
+		"https://example.com/docs/index.html",
+		"https://example.com/docs/guide/",
+		"https://example.com/docs/api/v1/users_list.html",
+	}, "/docs/")
+	tree := &doc2pdf.DocTree{Nodes: nodes}
+	var got []string
+	tree.Walk(func(node *doc2pdf.DocNode, level int) bool {
+		got = append(got, node.Title+" "+node.URL)
+		return true
+	})
+	want := []string{
+		"首页 https://example.com/docs/",
+		"guide https://example.com/docs/guide/",
+		"getting started https://example.com/docs/guide/getting-started.html",
+		"api ",
+		"v1 ",
+		"users list https://example.com/docs/api/v1/users_list.html",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %q", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("node %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package doc2pdf
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/go-rod/rod"
+)
+
+// maxNextPages 跟随下一页链接的最大页面数，避免链接成环时无限循环
+const maxNextPages = 5000
+
+// sphinxMenuSelector Read the Docs、Furo和Alabaster主题的侧边栏
+const sphinxMenuSelector = "div.wy-menu-vertical, div.sidebar-tree, div.sphinxsidebarwrapper"
+
+// DownloadSphinx 下载sphinx文档，mode为pdf或md
+//
+// createTime: 2026-10-18 09:24:41
+func DownloadSphinx(ctx context.Context, mainURL string, outputDir string, mode string, opts ...DocOption) (*RunSummary, error) {
+	return DownloadWithAdapter(ctx, &SphinxAdapter{}, mainURL, outputDir, mode, opts...)
+}
+
+func init() {
+	RegisterAdapter("sphinx", func() SiteAdapter {
+		return &SphinxAdapter{}
+	})
+}
+
+// SphinxAdapter sphinx适配器，支持Read the Docs、Furo和Alabaster主题
+type SphinxAdapter struct {
+	BaseAdapter
+	Next bool // 沿下一页链接查找侧边栏中没有的页面，没有侧边栏时总是跟随
+}
+
+// Name description
+func (a *SphinxAdapter) Name() string {
+	return "sphinx"
+}
+
+// Brief description
+func (a *SphinxAdapter) Brief() string {
+	return "sphinx/Read the Docs文档转换为pdf或markdown"
+}
+
+// Options description
+func (a *SphinxAdapter) Options() []AdapterOption {
+	return []AdapterOption{
+		{Name: "next", Brief: "沿下一页链接查找侧边栏中没有的页面，会逐个请求所有页面", Orphan: true},
+	}
+}
+
+// SetOption description
+func (a *SphinxAdapter) SetOption(name string, value string) error {
+	if name == "next" {
+		var err error
+		a.Next, err = ParseBoolOption(name, value)
+		return err
+	}
+	return a.BaseAdapter.SetOption(name, value)
+}
+
+// Init 入口页通常是文档首页，侧边栏中不包含
+func (a *SphinxAdapter) Init(doc *DocDownload) error {
+	doc.IsDownloadMain = true
+	return nil
+}
+
+// MenuRootSelector 部分主题没有侧边栏，由ParseMenu自行查找，找不到时只跟随下一页链接
+func (a *SphinxAdapter) MenuRootSelector() string {
+	return ""
+}
+
+// ParseMenu description
+//
+// createTime: 2026-10-18 09:24:41
+func (a *SphinxAdapter) ParseMenu(doc *DocDownload, root *rod.Element, level int, dirPath string) ([]*DocNode, error) {
+	// 菜单页面不受PageTimeout限制
+	page, err := doc.openPage(doc.MainURL, 0)
+	if err != nil {
+		return nil, err
+	}
+	has, sidebar, err := page.Has(sphinxMenuSelector)
+	if err != nil {
+		doc.closePage(page, 0)
+		return nil, err
+	}
+	if !has {
+		doc.closePage(page, 0)
+		log.Println("未找到侧边栏，跟随下一页链接查找页面")
+		return followSphinxNext(doc, nil)
+	}
+	menu, err := menuSelection(sidebar)
+	doc.closePage(page, 0)
+	if err != nil {
+		return nil, err
+	}
+	// 入口页已作为首页加入文档树
+	seen := map[string]bool{doc.SameSiteURL(doc.MainURL): true}
+	nodes := doc.menuNodes(ParseSphinxMenu(menu), seen)
+	if !a.Next {
+		return nodes, nil
+	}
+	return followSphinxNext(doc, nodes)
+}
+
+// MarkdownOptions description
+func (a *SphinxAdapter) MarkdownOptions() *MarkdownOptions {
+	return &MarkdownOptions{
+		ContentSelector: `div[itemprop="articleBody"], article[role="main"], div.body[role="main"]`,
+		Strip:           []string{"a.headerlink"},
+	}
+}
+
+// PrintOptions description
+func (a *SphinxAdapter) PrintOptions() *PrintOptions {
+	return &PrintOptions{PaperWidth: 15, PageHeight: 11, SinglePage: true}
+}
+
+// PreparePage 删除侧边栏、Read the Docs浮动菜单和版本选择
+//
+// createTime: 2026-10-18 09:24:41
+func (a *SphinxAdapter) PreparePage(doc *DocDownload, page *rod.Page) error {
+	if err := RemoveElements(page,
+		// Read the Docs
+		"nav.wy-nav-side",
+		"nav.wy-nav-top",
+		"div.rst-versions",
+		"readthedocs-flyout",
+		"readthedocs-notification",
+		"div.injected",
+		"div.version-switch",
+		"div[role=navigation]",
+		"footer",
+		// Furo
+		"div.mobile-header",
+		"aside.sidebar-drawer",
+		"aside.toc-drawer",
+		"div.related-pages",
+		// Alabaster
+		"div.sphinxsidebar",
+		"div.related",
+		"div.footer",
+		"a.headerlink",
+	); err != nil {
+		return err
+	}
+	if err := AddStyle(page, `
+		.wy-nav-content-wrap { margin-left: 0 !important; }
+		.wy-nav-content { max-width: none !important; }
+		div.bodywrapper { margin-left: 0 !important; }
+		div.document { width: auto !important; }
+		pre { white-space: pre-wrap; overflow-wrap: anywhere; }
+	`); err != nil {
+		return err
+	}
+	WaitImages(page, 30*time.Second)
+	return nil
+}
+
+// ParseSphinxMenu 解析侧边栏中的toctree，带分组标题时分组作为只有书签的菜单项
+//
+// createTime: 2026-10-18 09:24:41
+func ParseSphinxMenu(root *goquery.Selection) []MenuItem {
+	var parse func(ul *goquery.Selection) []MenuItem
+	parse = func(ul *goquery.Selection) []MenuItem {
+		items := make([]MenuItem, 0)
+		ul.ChildrenFiltered(`li[class*="toctree-l"]`).Each(func(_ int, li *goquery.Selection) {
+			a := li.ChildrenFiltered("a").First()
+			item := MenuItem{Title: menuText(a), Href: menuHref(a)}
+			if sub := li.ChildrenFiltered("ul").First(); sub.Length() > 0 {
+				item.Children = parse(sub)
+			}
+			items = append(items, item)
+		})
+		return items
+	}
+	items := make([]MenuItem, 0)
+	root.Find("ul").Each(func(_ int, ul *goquery.Selection) {
+		if ul.ChildrenFiltered("li.toctree-l1").Length() == 0 {
+			return
+		}
+		if caption := ul.Prev(); caption.Is(".caption") {
+			items = append(items, MenuItem{Title: menuText(caption), Children: parse(ul)})
+			return
+		}
+		items = append(items, parse(ul)...)
+	})
+	return items
+}
+
+// followSphinxNext 从入口页沿着下一页链接遍历全部页面，侧边栏中没有的页面
+// 按阅读顺序插入到前一个已知页面的子节点中
+//
+// createTime: 2026-10-18 09:24:41
+func followSphinxNext(doc *DocDownload, nodes []*DocNode) ([]*DocNode, error) {
+	known := make(map[string]*DocNode)
+	walkNodes(nodes, 0, func(node *DocNode, level int) bool {
+		if node.URL != "" {
+			known[node.URL] = node
+		}
+		return true
+	})
+	var (
+		parent   *DocNode
+		inserted int
+		current  = doc.MainURL
+	)
+	visited := map[string]bool{doc.SameSiteURL(current): true}
+	page, err := doc.FetchDocument(current)
+	if err != nil {
+		return nil, fmt.Errorf("获取入口页失败 %s: %w", current, err)
+	}
+	for i := 0; i < maxNextPages && doc.Context().Err() == nil; i++ {
+		href, ok := page.Find(`link[rel="next"]`).Attr("href")
+		if !ok {
+			break
+		}
+		base, err := url.Parse(current)
+		if err != nil {
+			return nil, err
+		}
+		ref, err := url.Parse(href)
+		if err != nil {
+			return nil, fmt.Errorf("下一页链接错误 %s: %w", current, err)
+		}
+		// 站外链接或回到已访问的页面时结束
+		next := doc.SameSiteURL(base.ResolveReference(ref).String())
+		if next == "" || visited[next] {
+			break
+		}
+		visited[next] = true
+		nextPage, err := doc.FetchDocument(next)
+		if err != nil {
+			return nil, fmt.Errorf("获取下一页失败 %s: %w", next, err)
+		}
+		current, page = next, nextPage
+
+		if node, ok := known[next]; ok {
+			parent = node
+			inserted = 0
+			continue
+		}
+		nextPage.Find("a.headerlink").Remove()
+		title := strings.TrimSpace(nextPage.Find("h1").First().Text())
+		if title == "" {
+			title = strings.TrimSpace(nextPage.Find("title").Text())
+		}
+		node := &DocNode{Title: title, URL: next}
+		log.Println("发现侧边栏中没有的页面", title, next)
+		known[next] = node
+		if parent == nil {
+			nodes = slices.Insert(nodes, inserted, node)
+		} else {
+			parent.Children = slices.Insert(parent.Children, inserted, node)
+		}
+		inserted++
+	}
+	if err := doc.Context().Err(); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
@@ -0,0 +1,129 @@
+package doc2pdf_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hailaz/doc2pdf"
+)
+
+// sphinxPage 生成带下一页链接的sphinx页面
+func sphinxPage(title string, next string, sidebar string) string {
+	link := ""
+	if next != "" {
+		link = `<link rel="next" href="` + next + `">`
+	}
+	return `<html><head><title>` + title + ` — docs</title>` + link + `</head><body>` + sidebar +
+		`<div role="main"><h1>` + title + `<a class="headerlink" href="#">¶</a></h1></div></body></html>`
+}
+
+const sphinxSidebar = `<nav class="wy-nav-side"><div class="wy-menu wy-menu-vertical">
+	<p class="caption"><span class="caption-text">User Guide</span></p>
+	<ul>
+		<li class="toctree-l1"><a class="reference internal" href="install.html">Install</a>
+			<ul><li class="toctree-l2"><a class="reference internal" href="install.html#pip">pip</a></li></ul>
+		</li>
+		<li class="toctree-l1"><a class="reference internal" href="usage.html">Usage</a></li>
+	</ul>
+</div></nav>`
+
+// sphinxPages 侧边栏中没有install/advanced.html，只能通过下一页链接找到
+var sphinxPages = map[string]string{
+	"/index.html":            sphinxPage("Home", "install.html", sphinxSidebar),
+	"/install.html":          sphinxPage("Install", "install/advanced.html", sphinxSidebar),
+	"/install/advanced.html": sphinxPage("Advanced", "../usage.html", sphinxSidebar),
+	"/usage.html":            sphinxPage("Usage", "", sphinxSidebar),
+}
+
+// TestParseSphinxMenu 分组标题作为只有书签的菜单项
+//
+// createTime: 2026-10-18 09:24:41
+func TestParseSphinxMenu(t *testing.T) {
+	items := doc2pdf.ParseSphinxMenu(menuFixture(t, sphinxSidebar, "div.wy-menu-vertical"))
+	if got := menuTitles(items); got != "User Guide[Install[pip] Usage]" {
+		t.Fatalf("menu = %s", got)
+	}
+	if items[0].Href != "" || items[0].Children[1].Href != "usage.html" {
+		t.Errorf("group = %q, usage = %q", items[0].Href, items[0].Children[1].Href)
+	}
+}
+
+// TestSphinxMenu 默认只使用侧边栏，不请求其它页面
+//
+// createTime: 2026-10-18 09:24:41
+func TestSphinxMenu(t *testing.T) {
+	doc, site := newFixtureSite(t, &doc2pdf.SphinxAdapter{}, sphinxPages, "/index.html")
+	tree, err := doc.Discover(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := treeTitles(tree.Nodes); got != "首页 User Guide[Install Usage]" {
+		t.Errorf("tree = %s", got)
+	}
+	if n := site.Requests("/install.html"); n != 0 {
+		t.Errorf("install.html requests = %d", n)
+	}
+}
+
+// TestSphinxNext 设置next时跟随下一页链接，把侧边栏中没有的页面插入到前一个页面下
+//
+// createTime: 2026-10-18 09:24:41
+func TestSphinxNext(t *testing.T) {
+	adapter := &doc2pdf.SphinxAdapter{}
+	if err := adapter.SetOption("next", "true"); err != nil {
+		t.Fatal(err)
+	}
+	doc := newFixtureDoc(t, adapter, sphinxPages, "/index.html")
+	tree, err := doc.Discover(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := treeTitles(tree.Nodes); got != "首页 User Guide[Install[Advanced] Usage]" {
+		t.Fatalf("tree = %s", got)
+	}
+	advanced := tree.Nodes[1].Children[0].Children[0]
+	if advanced.URL != strings.TrimSuffix(doc.MainURL, "index.html")+"install/advanced.html" {
+		t.Errorf("advanced = %s", advanced.URL)
+	}
+}
+
+// TestSphinxNextError 下一页获取失败时任务失败，不导出不完整的目录
+//
+// createTime: 2026-10-18 09:24:41
+func TestSphinxNextError(t *testing.T) {
+	adapter := &doc2pdf.SphinxAdapter{}
+	if err := adapter.SetOption("next", "true"); err != nil {
+		t.Fatal(err)
+	}
+	pages := map[string]string{
+		"/index.html": sphinxPage("Home", "install.html", sphinxSidebar),
+		"/usage.html": sphinxPage("Usage", "", sphinxSidebar),
+	}
+	doc := newFixtureDoc(t, adapter, pages, "/index.html")
+	_, err := doc.Discover(context.Background())
+	var pageErr *doc2pdf.PageError
+	if !errors.As(err, &pageErr) || pageErr.Stage != doc2pdf.StageMenu {
+		t.Errorf("err = %v", err)
+	}
+}
+
+// TestSphinxWithoutSidebar 没有侧边栏时按下一页链接的顺序生成目录
+//
+// createTime: 2026-10-18 09:24:41
+func TestSphinxWithoutSidebar(t *testing.T) {
+	pages := map[string]string{
+		"/index.html": sphinxPage("Home", "intro.html", ""),
+		"/intro.html": sphinxPage("Intro", "api.html", ""),
+		"/api.html":   sphinxPage("API", "index.html", ""),
+	}
+	doc := newFixtureDoc(t, &doc2pdf.SphinxAdapter{}, pages, "/index.html")
+	tree, err := doc.Discover(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := treeTitles(tree.Nodes); got != "首页 Intro API" {
+		t.Errorf("tree = %s", got)
+	}
+}
@@ -0,0 +1,178 @@
+package doc2pdf
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// stampMargin 页码和页眉到页面边缘的距离，单位点
+const stampMargin = 18
+
+// StampOptions 合并后添加到每页的页码和页眉
+type StampOptions struct {
+	PageNumber       bool     // 添加页码
+	PageNumberFormat string   // 页码格式，%p为页码，%P为总页数，默认为"%p / %P"
+	PageNumberPos    string   // 页码位置：tl、tc、tr、l、c、r、bl、bc、br，默认bc
+	Header           bool     // 添加页眉，内容为当前页所在的一级书签标题
+	HeaderPos        string   // 页眉位置，默认tc
+	Font             string   // 字体名，默认Helvetica，中文标题需要使用 pdfcpu fonts install 安装的字体
+	FontSize         int      // 字号，默认9
+	Color            string   // 颜色，默认#808080
+	Pages            []string // 添加的页面，pdfcpu页面选择语法，如 "3-"、"odd"、"1-,!2"，默认跳过封面和目录页
+}
+
+// WithStamp 合并后在每页添加页码和页眉
+//
+// createTime: 2026-10-18 10:10:48
+func WithStamp(opt *StampOptions) DocOption {
+	return func(doc *DocDownload) {
+		doc.Stamp = opt
+	}
+}
+
+// StampPDF 在pdf上添加页码和页眉，页眉按bms的一级书签划分页面范围，
+// front为开头的封面和目录页数，未指定Pages时跳过这些页面
+//
+// createTime: 2026-10-18 10:10:48
+func StampPDF(file string, bms []pdfcpu.Bookmark, front int, opt *StampOptions) error {
+	pageCount, err := api.PageCountFile(file)
+	if err != nil {
+		return err
+	}
+	selection := opt.Pages
+	if len(selection) == 0 {
+		selection = []string{fmt.Sprintf("%d-", front+1)}
+	}
+	pages, err := api.PagesForPageSelection(pageCount, selection, false, false)
+	if err != nil {
+		return err
+	}
+
+	m := make(map[int][]*model.Watermark)
+	if opt.PageNumber {
+		format := opt.PageNumberFormat
+		if format == "" {
+			format = "%p / %P"
+		}
+		pos, err := stampPos(opt.PageNumberPos, "bc")
+		if err != nil {
+			return err
+		}
+		wm, err := stampWatermark(format, pos, opt)
+		if err != nil {
+			return err
+		}
+		for pageNr, selected := range pages {
+			if selected {
+				m[pageNr] = append(m[pageNr], wm)
+			}
+		}
+	}
+	if opt.Header {
+		pos, err := stampPos(opt.HeaderPos, "tc")
+		if err != nil {
+			return err
+		}
+		for i, bm := range bms {
+			from, thru := bm.PageFrom, pageCount
+			if i+1 < len(bms) {
+				thru = bms[i+1].PageFrom - 1
+			}
+			if bm.Title == "" || thru < from {
+				continue
+			}
+			// %为页码占位符的前缀，标题中的需要转义
+			wm, err := stampWatermark(strings.ReplaceAll(bm.Title, "%", "%%"), pos, opt)
+			if err != nil {
+				return err
+			}
+			for pageNr := from; pageNr <= thru; pageNr++ {
+				if pages[pageNr] {
+					m[pageNr] = append(m[pageNr], wm)
+				}
+			}
+		}
+	}
+	if len(m) == 0 {
+		return nil
+	}
+	return api.AddWatermarksSliceMapFile(file, "", m, nil)
+}
+
+// Validate 检查位置、字体和颜色参数
+//
+// createTime: 2026-10-18 10:10:48
+func (opt *StampOptions) Validate() error {
+	for _, pos := range []string{opt.PageNumberPos, opt.HeaderPos} {
+		if _, err := stampPos(pos, "bc"); err != nil {
+			return err
+		}
+	}
+	_, err := stampWatermark("", "bc", opt)
+	return err
+}
+
+// stampWatermark 按参数生成文字印章
+//
+// createTime: 2026-10-18 10:10:48
+func stampWatermark(text string, pos string, opt *StampOptions) (*model.Watermark, error) {
+	fontName := opt.Font
+	if fontName == "" {
+		fontName = "Helvetica"
+	}
+	fontSize := opt.FontSize
+	if fontSize <= 0 {
+		fontSize = 9
+	}
+	color := opt.Color
+	if color == "" {
+		color = "#808080"
+	}
+	// 偏移到页边距内，顶部向下、底部向上、左侧向右、右侧向左
+	dx, dy := 0, 0
+	switch pos[len(pos)-1] {
+	case 'l':
+		dx = stampMargin
+	case 'r':
+		dx = -stampMargin
+	}
+	switch pos[0] {
+	case 't':
+		dy = -stampMargin
+	case 'b':
+		dy = stampMargin
+	}
+	desc := fmt.Sprintf("fontname:%s, points:%d, fillcolor:%s, position:%s, offset:%d %d, scalefactor:1 abs, rotation:0",
+		fontName, fontSize, color, pos, dx, dy)
+	return api.TextWatermark(text, desc, true, false, types.POINTS)
+}
+
+// stampPos 校验位置，为空时使用默认位置
+func stampPos(pos string, def string) (string, error) {
+	switch pos {
+	case "tl", "tc", "tr", "l", "c", "r", "bl", "bc", "br":
+		return pos, nil
+	case "":
+		return def, nil
+	}
+	return "", fmt.Errorf("位置参数错误: %s", pos)
+}
+
+// stampPDF 在合并后的pdf上添加页码和页眉，失败时保留未添加的文件
+//
+// createTime: 2026-10-18 10:10:48
+func (doc *DocDownload) stampPDF(front int) {
+	if doc.Stamp == nil || (!doc.Stamp.PageNumber && !doc.Stamp.Header) {
+		return
+	}
+	log.Println("添加页码和页眉", doc.OutputPDF())
+	if err := StampPDF(doc.OutputPDF(), doc.bookmark, front, doc.Stamp); err != nil {
+		log.Println("[err]添加页码和页眉失败:", err)
+	}
+}
@@ -0,0 +1,97 @@
+package doc2pdf_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hailaz/doc2pdf"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+)
+
+// stampTexts 读取每页印章的内容
+func stampTexts(t *testing.T, file string) map[int][]string {
+	t.Helper()
+	ctx, err := api.ReadContextFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	texts := make(map[int][]string)
+	for i := 1; i <= ctx.PageCount; i++ {
+		d, _, _, err := ctx.PageDict(i, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := ctx.DereferenceDict(d["Resources"])
+		if err != nil {
+			t.Fatal(err)
+		}
+		xObjects, err := ctx.DereferenceDict(res["XObject"])
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, o := range xObjects {
+			sd, _, err := ctx.DereferenceStreamDict(o)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := sd.Decode(); err != nil {
+				t.Fatal(err)
+			}
+			texts[i] = append(texts[i], string(sd.Content))
+		}
+	}
+	return texts
+}
+
+// TestStampPDF description
+//
+// createTime: 2026-10-18 10:10:48
+func TestStampPDF(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "out.pdf")
+	newTestPDF(t, file, 5)
+	bms := []pdfcpu.Bookmark{
+		{Title: "Start 100%", PageFrom: 2, Kids: []pdfcpu.Bookmark{{Title: "Install", PageFrom: 3}}},
+		{Title: "Config", PageFrom: 4},
+	}
+	// 第1页为封面，不添加
+	if err := doc2pdf.StampPDF(file, bms, 1, &doc2pdf.StampOptions{PageNumber: true, Header: true}); err != nil {
+		t.Fatal(err)
+	}
+	texts := stampTexts(t, file)
+	if len(texts[1]) != 0 {
+		t.Errorf("cover should not be stamped: %v", texts[1])
+	}
+	want := map[int][]string{
+		2: {"(2 / 5) Tj", "(Start 100%) Tj"},
+		3: {"(3 / 5) Tj", "(Start 100%) Tj"},
+		4: {"(4 / 5) Tj", "(Config) Tj"},
+		5: {"(5 / 5) Tj", "(Config) Tj"},
+	}
+	for pageNr, parts := range want {
+		content := strings.Join(texts[pageNr], "\n")
+		for _, part := range parts {
+			if !strings.Contains(content, part) {
+				t.Errorf("page %d missing %s: %s", pageNr, part, content)
+			}
+		}
+	}
+
+	file = filepath.Join(dir, "pages.pdf")
+	newTestPDF(t, file, 3)
+	opt := &doc2pdf.StampOptions{PageNumber: true, PageNumberFormat: "Page %p", Pages: []string{"1-", "!2"}}
+	if err := doc2pdf.StampPDF(file, bms, 0, opt); err != nil {
+		t.Fatal(err)
+	}
+	texts = stampTexts(t, file)
+	if len(texts[1]) != 1 || len(texts[2]) != 0 || len(texts[3]) != 1 {
+		t.Errorf("unexpected stamped pages %v", texts)
+	}
+
+	opt = &doc2pdf.StampOptions{Header: true, HeaderPos: "top"}
+	if err := doc2pdf.StampPDF(file, bms, 0, opt); err == nil {
+		t.Error("invalid position should fail")
+	}
+}
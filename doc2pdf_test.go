@@ -1,6 +1,7 @@
 package doc2pdf_test
 
 import (
+	"context"
 	"log"
 	"os"
 	"testing"
@@ -19,7 +20,7 @@ import (
 //
 // author: hailaz
 func TestDownloadHailaz(t *testing.T) {
-	doc2pdf.DownloadHailaz()
+	doc2pdf.DownloadHailaz(context.Background())
 }
 
 // TestDownloadGoFrame description
@@ -27,7 +28,7 @@ func TestDownloadHailaz(t *testing.T) {
 // createTime: 2023-07-28 14:46:43
 func TestDownloadGoFrame(t *testing.T) {
 	domain := "https://goframe.org"
-	doc2pdf.DownloadGoFrame(domain)
+	doc2pdf.DownloadGoFrame(context.Background(), domain, doc2pdf.DocDownloadModePDF)
 	// doc2pdf.DownloadDocusaurus(domain+"/quick/install", "./output/goframe/quick")
 }
 
@@ -37,7 +38,7 @@ func TestDownloadGoFrame(t *testing.T) {
 //
 // author: hailaz
 func TestDownloadGoFrameLatest(t *testing.T) {
-	doc2pdf.DownloadGoFrameLatest(doc2pdf.DocDownloadModePDF)
+	doc2pdf.DownloadGoFrameLatest(context.Background(), doc2pdf.DocDownloadModePDF)
 }
 
 // TestDownloadGoFrameLatestMD description
@@ -46,7 +47,7 @@ func TestDownloadGoFrameLatest(t *testing.T) {
 //
 // author: hailaz
 func TestDownloadGoFrameLatestMD(t *testing.T) {
-	doc2pdf.DownloadGoFrameLatest(doc2pdf.DocDownloadModeMD)
+	doc2pdf.DownloadGoFrameLatest(context.Background(), doc2pdf.DocDownloadModeMD)
 }
 
 // TestDownloadGoFrameAll description
@@ -80,7 +81,7 @@ func TestDownloadGoFrameAll(t *testing.T) {
 //
 // author: hailaz
 func TestDownloadRuanyifeng(t *testing.T) {
-	doc2pdf.DownloadRuanyifengWeekly()
+	doc2pdf.DownloadRuanyifengWeekly(context.Background())
 }
 
 var htmlpath = `output\goframe-latest-md-html\6-微服务开发\6-服务负载均衡1.html`
@@ -0,0 +1,221 @@
+package doc2pdf
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// tocLinkPrefix 目录页中链接的地址前缀，合并后替换为跳转到对应页面的内部链接
+const tocLinkPrefix = "https://doc2pdf.invalid/page/"
+
+// maxTOCRenders 目录页数变化后需要重新计算页码，页数稳定前最多渲染的次数
+const maxTOCRenders = 3
+
+// TOCEntry 目录项
+type TOCEntry struct {
+	Title string // 标题
+	Level int    // 层级，从0开始
+	Page  int    // 在合并后的pdf中的页码
+}
+
+// WithTOCPage 在合并后的pdf开头生成目录页
+//
+// createTime: 2026-10-18 10:03:05
+func WithTOCPage(enabled bool) DocOption {
+	return func(doc *DocDownload) {
+		doc.TOCPage = enabled
+	}
+}
+
+// TOCEntries 按先序展开书签为目录项
+//
+// createTime: 2026-10-18 10:03:05
+func TOCEntries(bms []pdfcpu.Bookmark) []TOCEntry {
+	entries := make([]TOCEntry, 0)
+	var walk func(bms []pdfcpu.Bookmark, level int)
+	walk = func(bms []pdfcpu.Bookmark, level int) {
+		for _, bm := range bms {
+			entries = append(entries, TOCEntry{Title: bm.Title, Level: level, Page: bm.PageFrom})
+			walk(bm.Kids, level+1)
+		}
+	}
+	walk(bms, 0)
+	return entries
+}
+
+// TOCHTML 生成目录页，标题和页码之间用点线连接，每项链接到 tocLinkPrefix+页码
+//
+// createTime: 2026-10-18 10:03:05
+func TOCHTML(title string, entries []TOCEntry) string {
+	var b strings.Builder
+	b.WriteString(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<style>
+	body { font-family: sans-serif; font-size: 14px; color: #222; margin: 0; padding: 48px 56px; }
+	h1 { font-size: 28px; margin: 0 0 24px; }
+	ol { list-style: none; margin: 0; padding: 0; }
+	li { break-inside: avoid; margin: 6px 0; }
+	li.level-0 { font-weight: bold; margin-top: 12px; }
+	a { display: flex; align-items: baseline; color: inherit; text-decoration: none; }
+	.title { overflow-wrap: anywhere; }
+	.leader { flex: 1; min-width: 24px; margin: 0 6px; border-bottom: 1px dotted #888; }
+	.page { font-variant-numeric: tabular-nums; }
+</style>
+</head>
+<body>
+`)
+	fmt.Fprintf(&b, "<h1>%s</h1>\n<ol>\n", html.EscapeString(title))
+	for _, entry := range entries {
+		fmt.Fprintf(&b, `<li class="level-%d" style="padding-left: %dem"><a href="%s%d"><span class="title">%s</span><span class="leader"></span><span class="page">%d</span></a></li>`+"\n",
+			min(entry.Level, 1), entry.Level*2, tocLinkPrefix, entry.Page, html.EscapeString(entry.Title), entry.Page)
+	}
+	b.WriteString("</ol>\n</body>\n</html>\n")
+	return b.String()
+}
+
+// renderTOC 通过浏览器渲染目录页，before为目录页前已有的页数。
+// 页码包含目录页本身，目录页数与上次渲染不一致时重新渲染
+//
+// createTime: 2026-10-18 10:03:05
+func (doc *DocDownload) renderTOC(bms []pdfcpu.Bookmark, before int) (string, int, error) {
+	file := doc.OutputDir() + ".toc" + doc.TempSuffix
+	pages := 1
+	for i := 0; i < maxTOCRenders; i++ {
+		entries := TOCEntries(shiftBookmarks(bms, before+pages))
+		if err := doc.HTMLToPDF(TOCHTML("目录", entries), file); err != nil {
+			os.Remove(file)
+			return "", 0, err
+		}
+		count, err := api.PageCountFile(file)
+		if err != nil {
+			os.Remove(file)
+			return "", 0, err
+		}
+		if count == pages {
+			break
+		}
+		pages = count
+	}
+	log.Printf("生成目录页%d页", pages)
+	return file, pages, nil
+}
+
+// HTMLToPDF 在浏览器中渲染html并保存为pdf，纸张宽度与适配器的打印参数一致，按页分页
+//
+// createTime: 2026-10-18 10:03:05
+func (doc *DocDownload) HTMLToPDF(content string, filePath string) error {
+	return doc.htmlToPDF(content, filePath, false)
+}
+
+// htmlToPDF 渲染html并保存为pdf，singlePage为true时打印为一个长页
+//
+// createTime: 2026-10-18 10:07:54
+func (doc *DocDownload) htmlToPDF(content string, filePath string, singlePage bool) error {
+	page, err := doc.OpenPage("about:blank")
+	if err != nil {
+		return err
+	}
+	defer doc.ClosePage(page)
+	if err := page.SetDocumentContent(content); err != nil {
+		return err
+	}
+	if err := page.WaitLoad(); err != nil {
+		return err
+	}
+	// PageTimeout为0表示不限制，等待图片仍需要上限
+	wait := doc.PageTimeout
+	if wait <= 0 {
+		wait = 30 * time.Second
+	}
+	WaitImages(page, wait)
+	opt := &PrintOptions{SinglePage: singlePage}
+	if printOpt := doc.Adapter.PrintOptions(); printOpt != nil {
+		opt.PaperWidth = printOpt.PaperWidth
+	}
+	return PageToPDFWithOptions(page, filePath, opt)
+}
+
+// LinkTOCPages 将from到thru页中指向 tocLinkPrefix 的链接替换为跳转到对应页面的内部链接
+//
+// createTime: 2026-10-18 10:03:05
+func LinkTOCPages(file string, from, thru int) error {
+	ctx, err := api.ReadContextFile(file)
+	if err != nil {
+		return err
+	}
+	if err := api.ValidateContext(ctx); err != nil {
+		return err
+	}
+	for pageNr := from; pageNr <= min(thru, ctx.PageCount); pageNr++ {
+		pageDict, _, _, err := ctx.PageDict(pageNr, false)
+		if err != nil {
+			return err
+		}
+		o, found := pageDict.Find("Annots")
+		if !found {
+			continue
+		}
+		annots, err := ctx.DereferenceArray(o)
+		if err != nil {
+			return err
+		}
+		for _, o := range annots {
+			d, err := ctx.DereferenceDict(o)
+			if err != nil || d == nil {
+				continue
+			}
+			if subtype := d.NameEntry("Subtype"); subtype == nil || *subtype != "Link" {
+				continue
+			}
+			target := tocLinkTarget(ctx.XRefTable, d)
+			if target < 1 || target > ctx.PageCount {
+				continue
+			}
+			_, indRef, _, err := ctx.PageDict(target, false)
+			if err != nil {
+				return err
+			}
+			d.Delete("A")
+			d["Dest"] = types.Array{*indRef, types.Name("Fit")}
+		}
+	}
+	tempFile := file + ".link"
+	if err := api.WriteContextFile(ctx, tempFile); err != nil {
+		os.Remove(tempFile)
+		return err
+	}
+	return os.Rename(tempFile, file)
+}
+
+// tocLinkTarget 链接指向的页码，不是目录页链接时返回0
+func tocLinkTarget(xRefTable *model.XRefTable, d types.Dict) int {
+	o, found := d.Find("A")
+	if !found {
+		return 0
+	}
+	action, err := xRefTable.DereferenceDict(o)
+	if err != nil || action == nil {
+		return 0
+	}
+	uri, err := xRefTable.DereferenceStringEntryBytes(action, "URI")
+	if err != nil || !strings.HasPrefix(string(uri), tocLinkPrefix) {
+		return 0
+	}
+	page, err := strconv.Atoi(strings.TrimPrefix(string(uri), tocLinkPrefix))
+	if err != nil {
+		return 0
+	}
+	return page
+}
@@ -0,0 +1,123 @@
+package doc2pdf_test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hailaz/doc2pdf"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// newTestPDF 生成指定页数的pdf
+func newTestPDF(t *testing.T, file string, pages int) {
+	t.Helper()
+	page := `{"content": {"box": [{"rect": [10, 10, 20, 20], "fillCol": "#FF0000"}]}}`
+	list := make([]string, 0, pages)
+	for i := 1; i <= pages; i++ {
+		list = append(list, `"`+strconv.Itoa(i)+`": `+page)
+	}
+	jsonFile := file + ".json"
+	os.WriteFile(jsonFile, []byte(`{"paper": "A4", "pages": {`+strings.Join(list, ",")+`}}`), 0o644)
+	if err := api.CreateFile("", jsonFile, file, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestTOCEntries description
+//
+// createTime: 2026-10-18 10:03:05
+func TestTOCEntries(t *testing.T) {
+	tree := &doc2pdf.DocTree{Nodes: []*doc2pdf.DocNode{
+		{Title: "快速开始", URL: "https://example.com/start", PageCount: 2, Children: []*doc2pdf.DocNode{
+			{Title: "安装", URL: "https://example.com/install", PageCount: 3},
+		}},
+		{Title: "<配置>", URL: "https://example.com/config", PageCount: 1},
+	}}
+	entries := doc2pdf.TOCEntries(tree.Bookmarks())
+	want := []doc2pdf.TOCEntry{
+		{Title: "快速开始", Level: 0, Page: 1},
+		{Title: "安装", Level: 1, Page: 3},
+		{Title: "<配置>", Level: 0, Page: 6},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("entries = %+v", entries)
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("entries[%d] = %+v, want %+v", i, entries[i], want[i])
+		}
+	}
+
+	html := doc2pdf.TOCHTML("目录", entries)
+	if !strings.Contains(html, "&lt;配置&gt;") || strings.Contains(html, "<配置>") {
+		t.Error("title should be escaped")
+	}
+	if !strings.Contains(html, `href="https://doc2pdf.invalid/page/6"`) {
+		t.Error("missing page link")
+	}
+}
+
+// TestLinkTOCPages description
+//
+// createTime: 2026-10-18 10:03:05
+func TestLinkTOCPages(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "out.pdf")
+	newTestPDF(t, filepath.Join(dir, "blank.pdf"), 3)
+	m := map[int][]model.AnnotationRenderer{
+		1: {
+			model.NewLinkAnnotation(*types.NewRectangle(10, 10, 200, 30), "", "", "", 0, nil, nil, "https://doc2pdf.invalid/page/3", nil, false, 0, model.BSSolid),
+			model.NewLinkAnnotation(*types.NewRectangle(10, 40, 200, 60), "", "", "", 0, nil, nil, "https://example.com/", nil, false, 0, model.BSSolid),
+		},
+	}
+	if err := api.AddAnnotationsMapFile(filepath.Join(dir, "blank.pdf"), file, m, nil, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc2pdf.LinkTOCPages(file, 1, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, err := api.ReadContextFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := api.ValidateContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	_, page3, _, err := ctx.PageDict(3, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	page1, _, _, err := ctx.PageDict(1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o, _ := page1.Find("Annots")
+	annots, err := ctx.DereferenceArray(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	internal, external := 0, 0
+	for _, o := range annots {
+		d, err := ctx.DereferenceDict(o)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dest, ok := d["Dest"].(types.Array); ok {
+			if ref, ok := dest[0].(types.IndirectRef); !ok || ref.ObjectNumber != page3.ObjectNumber {
+				t.Errorf("dest = %v", dest)
+			}
+			internal++
+		} else if _, ok := d.Find("A"); ok {
+			external++
+		}
+	}
+	if internal != 1 || external != 1 {
+		t.Errorf("internal = %d, external = %d", internal, external)
+	}
+}
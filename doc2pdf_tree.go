@@ -0,0 +1,211 @@
+package doc2pdf
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/gogf/gf/v2/encoding/gyaml"
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+)
+
+const (
+	// TreeFormatJSON json格式
+	TreeFormatJSON = "json"
+	// TreeFormatYAML yaml格式
+	TreeFormatYAML = "yaml"
+)
+
+// DocNode 文档树节点
+type DocNode struct {
+	Title       string            `json:"title" yaml:"title"`                                 // 书签标题
+	URL         string            `json:"url,omitempty" yaml:"url,omitempty"`                 // 页面地址，为空时只生成书签
+	Path        string            `json:"path,omitempty" yaml:"path,omitempty"`               // 输出文件路径
+	FrontMatter string            `json:"frontMatter,omitempty" yaml:"frontMatter,omitempty"` // markdown模式下添加到文件开头的内容
+	Meta        map[string]string `json:"meta,omitempty" yaml:"meta,omitempty"`               // 站点提供的页面元数据，如标签、最后修改时间，会记录到清单中
+	Children    []*DocNode        `json:"children,omitempty" yaml:"children,omitempty"`       // 子节点
+
+	PageCount   int    `json:"-" yaml:"-"` // 渲染后的pdf页数
+	Err         error  `json:"-" yaml:"-"` // 渲染错误
+	Fingerprint string `json:"-" yaml:"-"` // 页面内容指纹，增量导出时记录
+}
+
+// DocTree 文档树，由适配器解析菜单得到，各导出器根据文档树生成输出
+type DocTree struct {
+	MainURL string     `json:"mainURL,omitempty" yaml:"mainURL,omitempty"` // 文档入口地址
+	Nodes   []*DocNode `json:"nodes" yaml:"nodes"`                         // 顶层节点
+}
+
+// NewDocTree 创建文档树
+//
+// createTime: 2026-10-18 09:12:02
+func NewDocTree(mainURL string) *DocTree {
+	return &DocTree{
+		MainURL: mainURL,
+		Nodes:   make([]*DocNode, 0),
+	}
+}
+
+// Walk 按先序遍历文档树，level从0开始，fn返回false时不再遍历该节点的子节点
+//
+// createTime: 2026-10-18 09:12:02
+func (t *DocTree) Walk(fn func(node *DocNode, level int) bool) {
+	walkNodes(t.Nodes, 0, fn)
+}
+
+// walkNodes description
+func walkNodes(nodes []*DocNode, level int, fn func(node *DocNode, level int) bool) {
+	for _, node := range nodes {
+		if fn(node, level) {
+			walkNodes(node.Children, level+1, fn)
+		}
+	}
+}
+
+// Pages 按文档顺序返回需要渲染的节点
+//
+// createTime: 2026-10-18 09:12:02
+func (t *DocTree) Pages() []*DocNode {
+	pages := make([]*DocNode, 0)
+	t.Walk(func(node *DocNode, level int) bool {
+		if node.URL != "" {
+			pages = append(pages, node)
+		}
+		return true
+	})
+	return pages
+}
+
+// PageCount 渲染后的总页数
+//
+// createTime: 2026-10-18 09:12:02
+func (t *DocTree) PageCount() int {
+	total := 0
+	t.Walk(func(node *DocNode, level int) bool {
+		total += node.PageCount
+		return true
+	})
+	return total
+}
+
+// Bookmarks 根据文档树和渲染后的页数生成书签，书签层级与文档树一致
+//
+// createTime: 2026-10-18 09:12:02
+func (t *DocTree) Bookmarks() []pdfcpu.Bookmark {
+	total := t.PageCount()
+	pageFrom := 1
+	var build func(nodes []*DocNode) []pdfcpu.Bookmark
+	build = func(nodes []*DocNode) []pdfcpu.Bookmark {
+		bms := make([]pdfcpu.Bookmark, 0, len(nodes))
+		for _, node := range nodes {
+			from := pageFrom
+			// 末尾的页面渲染失败时，书签指向最后一页
+			if from > total {
+				from = total
+			}
+			pageFrom += node.PageCount
+			bm := pdfcpu.Bookmark{
+				Title:    node.Title,
+				PageFrom: from,
+			}
+			if len(node.Children) > 0 {
+				bm.Kids = build(node.Children)
+			}
+			bms = append(bms, bm)
+		}
+		return bms
+	}
+	return build(t.Nodes)
+}
+
+// shiftBookmarks 复制书签并将页码偏移delta，用于在文件前插入或删除页面后调整书签
+//
+// createTime: 2026-10-18 09:56:08
+func shiftBookmarks(bms []pdfcpu.Bookmark, delta int) []pdfcpu.Bookmark {
+	shifted := make([]pdfcpu.Bookmark, 0, len(bms))
+	for _, bm := range bms {
+		bm.PageFrom += delta
+		if bm.PageThru > 0 {
+			bm.PageThru += delta
+		}
+		bm.Kids = shiftBookmarks(bm.Kids, delta)
+		shifted = append(shifted, bm)
+	}
+	return shifted
+}
+
+// FillPaths 为缺少输出路径的页面节点生成路径，用于手工编辑过的目录文件
+//
+// createTime: 2026-10-18 09:15:17
+func (t *DocTree) FillPaths(dirPath string, ext string) {
+	var fill func(nodes []*DocNode, dir string)
+	fill = func(nodes []*DocNode, dir string) {
+		for i, node := range nodes {
+			name := fmt.Sprintf("%d-%s", i, validFileName.ReplaceAllString(node.Title, ""))
+			if node.URL != "" && node.Path == "" {
+				node.Path = path.Join(dir, name+ext)
+			}
+			fill(node.Children, path.Join(dir, name))
+		}
+	}
+	fill(t.Nodes, dirPath)
+}
+
+// Encode 将文档树编码为json或yaml
+//
+// createTime: 2026-10-18 09:15:17
+func (t *DocTree) Encode(format string) ([]byte, error) {
+	switch format {
+	case TreeFormatJSON:
+		return json.MarshalIndent(t, "", "  ")
+	case TreeFormatYAML, "yml":
+		return gyaml.Encode(t)
+	}
+	return nil, fmt.Errorf("不支持的目录格式: %s", format)
+}
+
+// DecodeDocTree 从json或yaml解码文档树
+//
+// createTime: 2026-10-18 09:15:17
+func DecodeDocTree(data []byte, format string) (*DocTree, error) {
+	tree := NewDocTree("")
+	var err error
+	switch format {
+	case TreeFormatJSON:
+		err = json.Unmarshal(data, tree)
+	case TreeFormatYAML, "yml":
+		err = gyaml.DecodeTo(data, tree)
+	default:
+		return nil, fmt.Errorf("不支持的目录格式: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+// LoadDocTree 读取目录文件，根据扩展名判断格式
+//
+// createTime: 2026-10-18 09:15:17
+func LoadDocTree(file string) (*DocTree, error) {
+	if !gfile.Exists(file) {
+		return nil, fmt.Errorf("目录文件不存在: %s", file)
+	}
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(file)), ".")
+	return DecodeDocTree(gfile.GetBytes(file), format)
+}
+
+// SaveDocTree 保存目录文件，根据扩展名判断格式
+//
+// createTime: 2026-10-18 09:15:17
+func SaveDocTree(tree *DocTree, file string) error {
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(file)), ".")
+	data, err := tree.Encode(format)
+	if err != nil {
+		return err
+	}
+	return gfile.PutBytes(file, data)
+}
@@ -0,0 +1,103 @@
+package doc2pdf_test
+
+import (
+	"testing"
+
+	"github.com/hailaz/doc2pdf"
+)
+
+// TestDocTreeBookmarks description
+//
+// createTime: 2026-10-18 09:12:02
+func TestDocTreeBookmarks(t *testing.T) {
+	tree := doc2pdf.NewDocTree("https://example.com/docs")
+	tree.Nodes = []*doc2pdf.DocNode{
+		{Title: "首页", URL: "https://example.com/docs", PageCount: 2},
+		{Title: "快速开始", Children: []*doc2pdf.DocNode{
+			{Title: "安装", URL: "https://example.com/docs/install", PageCount: 3},
+			{Title: "配置", URL: "https://example.com/docs/config", PageCount: 1},
+		}},
+		{Title: "失败页面", URL: "https://example.com/docs/broken"},
+	}
+
+	pages := tree.Pages()
+	if len(pages) != 4 {
+		t.Fatalf("pages: want 4, got %d", len(pages))
+	}
+	if pages[1].Title != "安装" {
+		t.Errorf("pages[1]: want 安装, got %s", pages[1].Title)
+	}
+
+	bms := tree.Bookmarks()
+	if len(bms) != 3 {
+		t.Fatalf("bookmarks: want 3, got %d", len(bms))
+	}
+	want := map[string]int{"首页": 1, "快速开始": 3, "失败页面": 6}
+	for _, bm := range bms {
+		if bm.PageFrom != want[bm.Title] {
+			t.Errorf("%s PageFrom: want %d, got %d", bm.Title, want[bm.Title], bm.PageFrom)
+		}
+	}
+	kids := bms[1].Kids
+	if len(kids) != 2 || kids[0].PageFrom != 3 || kids[1].PageFrom != 6 {
+		t.Errorf("kids: unexpected %+v", kids)
+	}
+}
+
+// TestDocTreeEncode description
+//
+// createTime: 2026-10-18 09:15:17
+func TestDocTreeEncode(t *testing.T) {
+	tree := doc2pdf.NewDocTree("https://example.com/docs")
+	tree.Nodes = []*doc2pdf.DocNode{
+		{Title: "首页", URL: "https://example.com/docs", Path: "output/index.pdf", PageCount: 2},
+		{Title: "快速开始", Children: []*doc2pdf.DocNode{
+			{Title: "安装", URL: "https://example.com/docs/install"},
+		}},
+	}
+	for _, format := range []string{doc2pdf.TreeFormatJSON, doc2pdf.TreeFormatYAML} {
+		data, err := tree.Encode(format)
+		if err != nil {
+			t.Fatalf("%s encode: %s", format, err)
+		}
+		got, err := doc2pdf.DecodeDocTree(data, format)
+		if err != nil {
+			t.Fatalf("%s decode: %s", format, err)
+		}
+		if got.MainURL != tree.MainURL || len(got.Nodes) != 2 {
+			t.Fatalf("%s: unexpected tree %+v", format, got)
+		}
+		if got.Nodes[0].Path != "output/index.pdf" || got.Nodes[0].PageCount != 0 {
+			t.Errorf("%s: unexpected node %+v", format, got.Nodes[0])
+		}
+		if len(got.Nodes[1].Children) != 1 || got.Nodes[1].Children[0].URL != "https://example.com/docs/install" {
+			t.Errorf("%s: unexpected children %+v", format, got.Nodes[1].Children)
+		}
+	}
+	if _, err := tree.Encode("xml"); err == nil {
+		t.Error("xml: want error")
+	}
+}
+
+// TestDocTreeFillPaths description
+//
+// createTime: 2026-10-18 09:15:17
+func TestDocTreeFillPaths(t *testing.T) {
+	tree := doc2pdf.NewDocTree("https://example.com/docs")
+	tree.Nodes = []*doc2pdf.DocNode{
+		{Title: "首页", URL: "https://example.com/docs", Path: "custom.pdf"},
+		{Title: "快速开始", Children: []*doc2pdf.DocNode{
+			{Title: "安装/配置", URL: "https://example.com/docs/install"},
+		}},
+	}
+	tree.FillPaths("output", ".pdf")
+	if tree.Nodes[0].Path != "custom.pdf" {
+		t.Errorf("keep path: got %s", tree.Nodes[0].Path)
+	}
+	if tree.Nodes[1].Path != "" {
+		t.Errorf("category path: got %s", tree.Nodes[1].Path)
+	}
+	if got := tree.Nodes[1].Children[0].Path; got != "output/1-快速开始/0-安装配置.pdf" {
+		t.Errorf("fill path: got %s", got)
+	}
+}
@@ -0,0 +1,200 @@
+package doc2pdf
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/go-rod/rod"
+)
+
+// DownloadVitePress 下载vitepress或vuepress文档，mode为pdf或md
+//
+// createTime: 2026-10-18 09:29:58
+func DownloadVitePress(ctx context.Context, mainURL string, outputDir string, mode string, opts ...DocOption) (*RunSummary, error) {
+	return DownloadWithAdapter(ctx, &VitePressAdapter{}, mainURL, outputDir, mode, opts...)
+}
+
+func init() {
+	RegisterAdapter("vitepress", func() SiteAdapter {
+		return &VitePressAdapter{}
+	})
+}
+
+// VitePressAdapter vitepress和vuepress适配器。
+// 多侧边栏的站点只显示与当前路径前缀匹配的侧边栏，所以只会导出入口页所在的侧边栏
+type VitePressAdapter struct {
+	BaseAdapter
+}
+
+// Name description
+func (a *VitePressAdapter) Name() string {
+	return "vitepress"
+}
+
+// Brief description
+func (a *VitePressAdapter) Brief() string {
+	return "vitepress/vuepress文档转换为pdf或markdown，只导出入口页所在的侧边栏"
+}
+
+// MenuRootSelector vitepress、vuepress 2和vuepress 1的侧边栏
+func (a *VitePressAdapter) MenuRootSelector() string {
+	return "nav#VPSidebarNav, aside.vp-sidebar > ul.vp-sidebar-items, aside.sidebar > ul.sidebar-links"
+}
+
+// ParseMenu description
+func (a *VitePressAdapter) ParseMenu(doc *DocDownload, root *rod.Element, level int, dirPath string) ([]*DocNode, error) {
+	if err := expandVuePressMenu(doc, root); err != nil {
+		return nil, fmt.Errorf("展开菜单失败: %w", err)
+	}
+	menu, err := menuSelection(root)
+	if err != nil {
+		return nil, err
+	}
+	return doc.menuNodes(ParseVitePressMenu(menu), make(map[string]bool)), nil
+}
+
+// MarkdownOptions description
+func (a *VitePressAdapter) MarkdownOptions() *MarkdownOptions {
+	return &MarkdownOptions{
+		ContentSelector: "div.vp-doc, div.theme-default-content",
+		Strip: []string{
+			"a.header-anchor",
+			"button.copy",
+			"span.lang",
+		},
+	}
+}
+
+// PrintOptions description
+func (a *VitePressAdapter) PrintOptions() *PrintOptions {
+	return &PrintOptions{PaperWidth: 15, PageHeight: 11, SinglePage: true}
+}
+
+// PreparePage 隐藏导航栏、侧边栏、大纲和页脚
+//
+// createTime: 2026-10-18 09:29:58
+func (a *VitePressAdapter) PreparePage(doc *DocDownload, page *rod.Page) error {
+	if err := RemoveElements(page,
+		// vitepress
+		"div.VPNav",
+		"div.VPLocalNav",
+		"aside.VPSidebar",
+		"div.VPDoc div.aside",
+		"footer.VPDocFooter",
+		"footer.VPFooter",
+		"div.VPBackdrop",
+		"a.VPSkipLink",
+		// vuepress
+		"header.navbar",
+		"header.vp-navbar",
+		"aside.sidebar",
+		"aside.vp-sidebar",
+		"footer.page-edit",
+		"footer.vp-page-meta",
+		"nav.page-nav",
+		"nav.vp-page-nav",
+	); err != nil {
+		return err
+	}
+	if err := AddStyle(page, `
+		.VPContent, .VPContent.has-sidebar { padding: 0 !important; margin: 0 !important; }
+		.VPDoc, .VPDoc .container, .VPDoc .content, .VPDoc .content-container { max-width: none !important; padding: 0 1rem !important; }
+		.page, .vp-page { padding: 0 !important; }
+		.theme-default-content { max-width: none !important; }
+		pre, pre code { white-space: pre-wrap !important; overflow-wrap: anywhere; }
+	`); err != nil {
+		return err
+	}
+	if err := ScrollToBottom(page); err != nil {
+		return err
+	}
+	WaitImages(page, 30*time.Second)
+	return nil
+}
+
+// expandVuePressMenu 展开vuepress 1的侧边栏分组。分组同时只能展开一个，
+// 逐个点击后复制子菜单，全部点击完成后再放回对应的分组中。
+// vitepress和vuepress 2折叠的分组也在DOM中，不需要展开
+//
+// createTime: 2026-10-18 09:29:58
+func expandVuePressMenu(doc *DocDownload, root *rod.Element) error {
+	_, err := root.Eval(`async (delay) => {
+		const sleep = (ms) => new Promise((resolve) => setTimeout(resolve, ms));
+		const groups = Array.from(this.querySelectorAll('section.sidebar-group.collapsable'));
+		const lists = [];
+		for (const group of groups) {
+			let ul = group.querySelector(':scope > ul');
+			const heading = group.querySelector(':scope > a, :scope > p');
+			if (!ul && heading) {
+				heading.click();
+				await sleep(delay);
+				ul = group.querySelector(':scope > ul');
+			}
+			lists.push(ul ? ul.cloneNode(true) : null);
+		}
+		groups.forEach((group, i) => {
+			if (lists[i] && !group.querySelector(':scope > ul')) {
+				group.appendChild(lists[i]);
+			}
+		});
+	}`, doc.OpDelay.Milliseconds())
+	return err
+}
+
+// ParseVitePressMenu 解析vitepress、vuepress 2和vuepress 1的侧边栏
+//
+// createTime: 2026-10-18 09:29:58
+func ParseVitePressMenu(root *goquery.Selection) []MenuItem {
+	if root.Find(".VPSidebarItem").Length() > 0 {
+		var parse func(container *goquery.Selection) []MenuItem
+		parse = func(container *goquery.Selection) []MenuItem {
+			items := make([]MenuItem, 0)
+			container.ChildrenFiltered(".VPSidebarItem").Each(func(_ int, el *goquery.Selection) {
+				head := el.ChildrenFiltered(".item").First()
+				text := head.Find(".text").First()
+				if text.Length() == 0 {
+					text = head
+				}
+				item := MenuItem{Title: menuText(text), Href: menuHref(head.Find("a").First())}
+				if sub := el.ChildrenFiltered(".items").First(); sub.Length() > 0 {
+					item.Children = parse(sub)
+				}
+				items = append(items, item)
+			})
+			return items
+		}
+		items := make([]MenuItem, 0)
+		root.ChildrenFiltered(".group").Each(func(_ int, group *goquery.Selection) {
+			items = append(items, parse(group)...)
+		})
+		return items
+	}
+	// vuepress
+	var parse func(ul *goquery.Selection) []MenuItem
+	parse = func(ul *goquery.Selection) []MenuItem {
+		items := make([]MenuItem, 0)
+		ul.Children().Each(func(_ int, li *goquery.Selection) {
+			box := li.ChildrenFiltered("section.sidebar-group").First()
+			if box.Length() == 0 {
+				box = li
+			}
+			heading := box.ChildrenFiltered("a, p").First()
+			if heading.Length() == 0 {
+				return
+			}
+			a := heading
+			if !heading.Is("a") {
+				a = heading.Find("a").First()
+			}
+			item := MenuItem{Title: menuText(heading), Href: menuHref(a)}
+			if sub := box.ChildrenFiltered("ul").First(); sub.Length() > 0 {
+				item.Children = parse(sub)
+			}
+			items = append(items, item)
+		})
+		return items
+	}
+	return parse(root)
+}
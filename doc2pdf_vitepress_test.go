@@ -0,0 +1,117 @@
+package doc2pdf_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hailaz/doc2pdf"
+)
+
+// vitePressSidebars vitepress、vuepress 2和vuepress 1的侧边栏，
+// expanded为浏览器中展开后的菜单，为空时与sidebar相同
+var vitePressSidebars = []struct {
+	name     string
+	sidebar  string
+	expanded string
+	want     string
+}{
+	{
+		name: "vitepress",
+		sidebar: `<aside class="VPSidebar"><nav class="nav" id="VPSidebarNav">
+			<div class="group"><section class="VPSidebarItem level-0">
+				<div class="item"><h2 class="text">Guide</h2></div>
+				<div class="items">
+					<div class="VPSidebarItem level-1"><div class="item"><a class="VPLink link" href="/guide/start"><p class="text">Getting Started</p></a></div></div>
+				</div>
+			</section></div>
+			<div class="group"><section class="VPSidebarItem level-0 collapsible collapsed">
+				<div class="item"><a class="VPLink link" href="/ref/"><h2 class="text">Reference</h2></a></div>
+				<div class="items">
+					<div class="VPSidebarItem level-1"><div class="item"><a class="VPLink link" href="/ref/config"><p class="text">Config</p></a></div></div>
+				</div>
+			</section></div>
+		</nav></aside>`,
+		want: "Guide[Getting Started] Reference[Config]",
+	},
+	{
+		name: "vuepress2",
+		sidebar: `<aside class="vp-sidebar"><ul class="vp-sidebar-items">
+			<li><p class="vp-sidebar-item vp-sidebar-heading">Guide</p>
+				<ul class="vp-sidebar-children">
+					<li><a class="vp-sidebar-item" href="/guide/">Introduction</a></li>
+					<li><a class="vp-sidebar-item" href="/guide/start.html">Getting Started</a></li>
+				</ul>
+			</li>
+			<li><a class="vp-sidebar-item" href="/faq.html">FAQ</a></li>
+		</ul></aside>`,
+		want: "Guide[Introduction Getting Started] FAQ",
+	},
+	{
+		// vuepress 1的分组点击后才渲染子菜单，同时只展开一个
+		name: "vuepress1",
+		sidebar: `<aside class="sidebar"><ul class="sidebar-links">
+			<li><a class="sidebar-link" href="/">Home</a></li>
+			<li><section class="sidebar-group collapsable depth-0"><p class="sidebar-heading" data-link="/guide/start.html">Guide</p></section></li>
+			<li><section class="sidebar-group collapsable depth-0"><p class="sidebar-heading" data-link="/api/client.html">API</p></section></li>
+		</ul></aside>
+		<script>
+			document.querySelectorAll('p.sidebar-heading').forEach(p => p.addEventListener('click', () => {
+				document.querySelectorAll('section.sidebar-group > ul').forEach(ul => ul.remove());
+				setTimeout(() => {
+					const ul = document.createElement('ul');
+					ul.className = 'sidebar-links sidebar-group-items';
+					ul.innerHTML = '<li><a class="sidebar-link" href="' + p.dataset.link + '">' + p.textContent + ' Page</a></li>';
+					p.parentElement.appendChild(ul);
+				}, 50);
+			}));
+		</script>`,
+		expanded: `<aside class="sidebar"><ul class="sidebar-links">
+			<li><a class="sidebar-link" href="/">Home</a></li>
+			<li><section class="sidebar-group collapsable depth-0"><p class="sidebar-heading">Guide</p>
+				<ul class="sidebar-links sidebar-group-items"><li><a class="sidebar-link" href="/guide/start.html">Guide Page</a></li></ul>
+			</section></li>
+			<li><section class="sidebar-group collapsable depth-0"><p class="sidebar-heading">API</p>
+				<ul class="sidebar-links sidebar-group-items"><li><a class="sidebar-link" href="/api/client.html">API Page</a></li></ul>
+			</section></li>
+		</ul></aside>`,
+		want: "Home Guide[Guide Page] API[API Page]",
+	},
+}
+
+// TestParseVitePressMenu 解析展开后的vitepress、vuepress 2和vuepress 1侧边栏
+//
+// createTime: 2026-10-18 09:29:58
+func TestParseVitePressMenu(t *testing.T) {
+	selector := (&doc2pdf.VitePressAdapter{}).MenuRootSelector()
+	for _, c := range vitePressSidebars {
+		t.Run(c.name, func(t *testing.T) {
+			page := c.expanded
+			if page == "" {
+				page = c.sidebar
+			}
+			items := doc2pdf.ParseVitePressMenu(menuFixture(t, page, selector))
+			if got := menuTitles(items); got != c.want {
+				t.Errorf("menu = %s", got)
+			}
+		})
+	}
+}
+
+// TestVitePressMenu 在浏览器中展开侧边栏后解析，vuepress 1的分组需要逐个点击
+//
+// createTime: 2026-10-18 09:29:58
+func TestVitePressMenu(t *testing.T) {
+	for _, c := range vitePressSidebars {
+		t.Run(c.name, func(t *testing.T) {
+			pages := map[string]string{"/": `<html><body>` + c.sidebar + `<main>doc</main></body></html>`}
+			doc := newFixtureDoc(t, &doc2pdf.VitePressAdapter{}, pages, "/")
+			tree, err := doc.Discover(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := treeTitles(tree.Nodes); got != c.want {
+				t.Errorf("tree = %s", got)
+			}
+		})
+	}
+}